@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -15,10 +16,16 @@ import (
 const listenerPort = ":8080"
 
 func main() {
+	// requests are cancelled on shutdown so that streams which never end
+	// (followed files) do not keep the server from shutting down
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
 	// server is created
 	server := &http.Server{
-		Addr:    listenerPort,
-		Handler: filemanager.Routes(),
+		Addr:        listenerPort,
+		Handler:     filemanager.Routes(),
+		BaseContext: func(net.Listener) context.Context { return ctx },
 	}
 
 	errChan := make(chan error, 1)
@@ -33,8 +40,9 @@ func main() {
 
 	select {
 	case <-signalChan:
+		cancel()
 		if err := server.Shutdown(context.Background()); err != nil {
-			fmt.Errorf("shutting down server: %v", err)
+			fmt.Printf("shutting down server: %v", err)
 		}
 		<-errChan
 	case <-errChan:
@@ -0,0 +1,212 @@
+package filemanager
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// analysisOptions are the options shared by all the word statistics requests
+// StopWords are names of built in (language code) or uploaded stop word lists
+// Stem is reducing english words to their stem
+// the file filter is selecting the files which are analysed, binary files
+// are only analysed with IncludeBinary
+type analysisOptions struct {
+	fileFilter
+	Tokenizer     tokenizerOptions `json:"tokenizer"`
+	StopWords     []string         `json:"stop_words"`
+	Stem          bool             `json:"stem"`
+	IncludeBinary bool             `json:"include_binary"`
+}
+
+// analyzer is turning lines of text into words as configured by a request
+type analyzer struct {
+	tokenizer Tokenizer
+	stopWords map[string]bool
+	options   analysisOptions
+}
+
+// fileAnalysis is representing everything counted while reading a file
+// Tokens are the words in the order they appear in the file
+type fileAnalysis struct {
+	Name       string
+	Counts     map[string]int
+	Tokens     []wordPosition
+	Words      int
+	Lines      int
+	Chars      int
+	Bytes      int
+	WordLength int
+
+	// sentence is the index of the current sentence and run of the current
+	// run of adjacent words, a run is broken by a sentence end or a stop word
+	sentence     int
+	run          int
+	runWords     int
+	sentenceEnd  bool
+	sentenceSeen bool
+}
+
+// wordPosition is a word of a file with the place it was found at
+// Line is 1 based, Start and End are byte offsets within the line
+// Sentence and Run are the indexes of the sentence and run of adjacent words
+type wordPosition struct {
+	Word     string
+	Line     int
+	Start    int
+	End      int
+	Sentence int
+	Run      int
+}
+
+// newAnalyzer is creating an analyzer for the request options
+func newAnalyzer(options analysisOptions) (*analyzer, error) {
+	tokenizer, err := newTokenizer(options.Tokenizer)
+	if err != nil {
+		return nil, err
+	}
+	stopWords, err := loadStopWords(options.StopWords, options.Tokenizer)
+	if err != nil {
+		return nil, err
+	}
+	return &analyzer{
+		tokenizer: tokenizer,
+		stopWords: stopWords,
+		options:   options,
+	}, nil
+}
+
+// words is returning the normalized words of a line, stop words are marked
+// entities are neither stop words nor stemmed
+// word positions are still pointing into the line
+func (a *analyzer) words(line string) []token {
+	tokens := a.tokenizer.Tokenize(line)
+	words := tokens[:0]
+	for _, tok := range tokens {
+		if tok.Entity != "" {
+			tok.Text = normalizeEntity(entity{Type: tok.Entity, Text: tok.Text}, a.options.Tokenizer)
+			words = append(words, tok)
+			continue
+		}
+		tok.Text = normalizeWord(tok.Text, a.options.Tokenizer)
+		if tok.Text == "" {
+			continue
+		}
+		if a.stopWords[tok.Text] {
+			tok.Stop = true
+		} else if a.options.Stem {
+			tok.Text = stem(tok.Text)
+		}
+		words = append(words, tok)
+	}
+	return words
+}
+
+// term is returning a single word of a request normalized like the words of the files
+func (a *analyzer) term(word string) (string, error) {
+	for _, tok := range a.words(word) {
+		if !tok.Stop {
+			return tok.Text, nil
+		}
+	}
+	return "", fmt.Errorf("word %q is empty or a stop word", word)
+}
+
+// newFileAnalysis is creating an empty analysis of a file
+func newFileAnalysis(name string) *fileAnalysis {
+	return &fileAnalysis{
+		Name:   name,
+		Counts: make(map[string]int),
+	}
+}
+
+// addLine is counting a single line (including its line ending)
+// a blank line or a sentence terminator ends the sentence
+func (fa *fileAnalysis) addLine(line string, a *analyzer) {
+	fa.Lines++
+	fa.Bytes += len(line)
+	fa.Chars += utf8.RuneCountInString(line)
+	if strings.TrimSpace(line) == "" {
+		fa.sentenceEnd = true
+		return
+	}
+
+	from := 0
+	for _, word := range a.words(line) {
+		if hasSentenceEnd(line, from, word.Start) {
+			fa.sentenceEnd = true
+		}
+		from = word.Start
+		fa.addWord(word)
+	}
+	if hasSentenceEnd(line, from, len(line)) {
+		fa.sentenceEnd = true
+	}
+}
+
+// addWord is counting a word of the current line
+// stop words are not counted but they break the run of adjacent words
+func (fa *fileAnalysis) addWord(word token) {
+	if fa.sentenceEnd && fa.sentenceSeen {
+		fa.sentence++
+		fa.breakRun()
+	}
+	fa.sentenceEnd = false
+	fa.sentenceSeen = true
+
+	if word.Stop {
+		fa.breakRun()
+		return
+	}
+
+	fa.Counts[word.Text]++
+	fa.Words++
+	fa.WordLength += utf8.RuneCountInString(word.Text)
+	fa.Tokens = append(fa.Tokens, wordPosition{
+		Word:     word.Text,
+		Line:     fa.Lines,
+		Start:    word.Start,
+		End:      word.End,
+		Sentence: fa.sentence,
+		Run:      fa.run,
+	})
+	fa.runWords++
+}
+
+// breakRun is starting a new run of adjacent words
+func (fa *fileAnalysis) breakRun() {
+	if fa.runWords > 0 {
+		fa.run++
+		fa.runWords = 0
+	}
+}
+
+// sentenceCount is returning the number of sentences which have words
+func (fa *fileAnalysis) sentenceCount() int {
+	if !fa.sentenceSeen {
+		return 0
+	}
+	return fa.sentence + 1
+}
+
+// hasSentenceEnd is checking whether line[from:to] has a sentence terminator
+// a terminator has to be followed by space, a closing quote or the line end
+// so that numbers (3.14) and names (example.com) do not end sentences
+func hasSentenceEnd(line string, from, to int) bool {
+	for i, r := range line[from:to] {
+		if !isSentenceTerminator(r) {
+			continue
+		}
+		next, _ := utf8.DecodeRuneInString(line[from+i+utf8.RuneLen(r):])
+		if next == utf8.RuneError || unicode.IsSpace(next) || isSentenceTerminator(next) || strings.ContainsRune(`"')]”’»`, next) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSentenceTerminator is checking whether a rune ends a sentence
+func isSentenceTerminator(r rune) bool {
+	return strings.ContainsRune(".!?…。！？", r)
+}
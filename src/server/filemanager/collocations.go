@@ -0,0 +1,224 @@
+package filemanager
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+)
+
+// defaultCollocationWindow is the largest distance between co-occurring words by default
+const defaultCollocationWindow = 5
+
+// collocation measures
+const (
+	measurePMI = "pmi"
+	measureLLR = "llr"
+)
+
+// collocationRequest is representing the collocation request
+// Window is the largest distance in words between two co-occurring words
+// Measure ranks the pairs, pmi (pointwise mutual information) or llr (log-likelihood ratio)
+// Word restricts the pairs to the ones containing the word, whole corpus when empty
+// MinCount drops the pairs co-occurring less often
+type collocationRequest struct {
+	analysisOptions
+	Word     string `json:"word"`
+	Window   int    `json:"window"`
+	Measure  string `json:"measure"`
+	MinCount int    `json:"min_count"`
+	Limit    int    `json:"limit"`
+}
+
+// collocation is representing two words co-occurring with their score
+// the given word is always First, otherwise the words are in alphabetical order
+type collocation struct {
+	First  string  `json:"first"`
+	Second string  `json:"second"`
+	Count  int     `json:"count"`
+	Score  float64 `json:"score"`
+}
+
+// collocationResponse is representing the top collocations
+type collocationResponse struct {
+	Word         string        `json:"word,omitempty"`
+	Measure      string        `json:"measure"`
+	Window       int           `json:"window"`
+	Total        int           `json:"total"`
+	Collocations []collocation `json:"collocations"`
+}
+
+// wordPair is an unordered pair of words, First <= Second
+type wordPair struct {
+	First  string
+	Second string
+}
+
+// coOccurrences is the symmetric co-occurrence matrix of a corpus
+// marginals are the row sums and total the sum of all the cells
+type coOccurrences struct {
+	pairs     map[wordPair]int
+	marginals map[string]int
+	total     int
+}
+
+// Collocations is returning the word pairs co-occurring within a window
+// ranked by pmi or log-likelihood, for a word or the whole corpus
+func (fm *fileManager) Collocations(r *http.Request) (interface{}, error) {
+	collocationRequest := &collocationRequest{}
+	if err := decodeBody(r, collocationRequest); err != nil {
+		return nil, fmt.Errorf("collocation request body decoding failed with %v", err)
+	}
+
+	if collocationRequest.Window == 0 {
+		collocationRequest.Window = defaultCollocationWindow
+	}
+	if collocationRequest.Window < 1 {
+		return nil, &statusError{code: http.StatusBadRequest, err: fmt.Errorf("invalid window %v", collocationRequest.Window)}
+	}
+	if collocationRequest.Measure == "" {
+		collocationRequest.Measure = measurePMI
+	}
+	if collocationRequest.Measure != measurePMI && collocationRequest.Measure != measureLLR {
+		return nil, &statusError{code: http.StatusBadRequest, err: fmt.Errorf("invalid measure %v, measure has to be %v or %v", collocationRequest.Measure, measurePMI, measureLLR)}
+	}
+	if collocationRequest.MinCount <= 0 {
+		collocationRequest.MinCount = 1
+	}
+	if collocationRequest.Limit <= 0 {
+		collocationRequest.Limit = defaultKeywordLimit
+	}
+
+	analyzer, err := newAnalyzer(collocationRequest.analysisOptions)
+	if err != nil {
+		return nil, err
+	}
+	word := ""
+	if collocationRequest.Word != "" {
+		if word, err = analyzer.term(collocationRequest.Word); err != nil {
+			return nil, err
+		}
+	}
+
+	analyses, err := analyzeFiles(analyzer)
+	if err != nil {
+		return nil, err
+	}
+
+	matrix := countCoOccurrences(analyses, collocationRequest.Window)
+	collocations := []collocation{}
+	for pair, count := range matrix.pairs {
+		if count < collocationRequest.MinCount {
+			continue
+		}
+		if word != "" {
+			if pair.Second == word {
+				pair.First, pair.Second = pair.Second, pair.First
+			} else if pair.First != word {
+				continue
+			}
+		}
+		score := matrix.pmi(pair, count)
+		if collocationRequest.Measure == measureLLR {
+			score = matrix.logLikelihood(pair, count)
+		}
+		collocations = append(collocations, collocation{
+			First:  pair.First,
+			Second: pair.Second,
+			Count:  count,
+			Score:  score,
+		})
+	}
+	sort.Slice(collocations, func(i, j int) bool {
+		a, b := collocations[i], collocations[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.First != b.First {
+			return a.First < b.First
+		}
+		return a.Second < b.Second
+	})
+
+	total := len(collocations)
+	if total > collocationRequest.Limit {
+		collocations = collocations[:collocationRequest.Limit]
+	}
+	return &collocationResponse{
+		Word:         word,
+		Measure:      collocationRequest.Measure,
+		Window:       collocationRequest.Window,
+		Total:        total,
+		Collocations: collocations,
+	}, nil
+}
+
+// countCoOccurrences is counting the pairs of different words at most window
+// words apart, pairs do not cross sentence or file boundaries
+func countCoOccurrences(analyses []*fileAnalysis, window int) *coOccurrences {
+	matrix := &coOccurrences{
+		pairs:     make(map[wordPair]int),
+		marginals: make(map[string]int),
+	}
+	for _, analysis := range analyses {
+		tokens := analysis.Tokens
+		for i := range tokens {
+			for j := i + 1; j < len(tokens) && j-i <= window; j++ {
+				if tokens[j].Sentence != tokens[i].Sentence {
+					break
+				}
+				first, second := tokens[i].Word, tokens[j].Word
+				if first == second {
+					continue
+				}
+				if second < first {
+					first, second = second, first
+				}
+				matrix.pairs[wordPair{First: first, Second: second}]++
+				matrix.marginals[first]++
+				matrix.marginals[second]++
+				matrix.total += 2
+			}
+		}
+	}
+	return matrix
+}
+
+// pmi is the pointwise mutual information of a pair in bits
+// log2(p(a,b) / (p(a) * p(b)))
+func (m *coOccurrences) pmi(pair wordPair, count int) float64 {
+	return math.Log2(float64(count) * float64(m.total) /
+		(float64(m.marginals[pair.First]) * float64(m.marginals[pair.Second])))
+}
+
+// logLikelihood is the Dunning log-likelihood ratio (G²) of the 2x2
+// contingency table of a pair
+func (m *coOccurrences) logLikelihood(pair wordPair, count int) float64 {
+	a, b := m.marginals[pair.First], m.marginals[pair.Second]
+	return logLikelihoodRatio(count, a-count, b-count, m.total-a-b+count)
+}
+
+// logLikelihoodRatio is the G² statistic of the contingency table
+// | k11 k12 |
+// | k21 k22 |
+func logLikelihoodRatio(k11, k12, k21, k22 int) float64 {
+	n := float64(k11 + k12 + k21 + k22)
+	rows := []float64{float64(k11 + k12), float64(k21 + k22)}
+	cols := []float64{float64(k11 + k21), float64(k12 + k22)}
+	cells := [][]int{{k11, k12}, {k21, k22}}
+
+	g2 := 0.0
+	for i, row := range cells {
+		for j, k := range row {
+			if k == 0 {
+				continue
+			}
+			expected := rows[i] * cols[j] / n
+			g2 += float64(k) * math.Log(float64(k)/expected)
+		}
+	}
+	return 2 * g2
+}
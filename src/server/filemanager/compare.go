@@ -0,0 +1,156 @@
+package filemanager
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// compareRequest is representing the corpus comparison (keyness) request
+// Target and Reference select the two sets of files, when Reference selects
+// nothing it is all the files not in Target
+// the file filter of the analysis options restricts both the sets
+type compareRequest struct {
+	analysisOptions
+	Target    fileFilter `json:"target"`
+	Reference fileFilter `json:"reference"`
+	Limit     int        `json:"limit"`
+}
+
+// keyWord is representing a word with its frequencies in the two sets
+// frequencies are per million words and LogLikelihood is the keyness score
+type keyWord struct {
+	Word               string  `json:"word"`
+	TargetCount        int     `json:"target_count"`
+	ReferenceCount     int     `json:"reference_count"`
+	TargetFrequency    float64 `json:"target_frequency"`
+	ReferenceFrequency float64 `json:"reference_frequency"`
+	LogLikelihood      float64 `json:"log_likelihood"`
+}
+
+// corpusSet is representing the files and the number of words of a set
+type corpusSet struct {
+	Files []string `json:"files"`
+	Words int      `json:"words"`
+}
+
+// compareResponse is representing the words over and under represented
+// in the target set relative to the reference set
+type compareResponse struct {
+	Target    corpusSet `json:"target"`
+	Reference corpusSet `json:"reference"`
+	Overused  []keyWord `json:"overused"`
+	Underused []keyWord `json:"underused"`
+}
+
+// Compare is returning the words most over and under represented in one
+// set of files relative to another ranked by log-likelihood
+func (fm *fileManager) Compare(r *http.Request) (interface{}, error) {
+	compareRequest := &compareRequest{}
+	if err := decodeBody(r, compareRequest); err != nil {
+		return nil, fmt.Errorf("compare request body decoding failed with %v", err)
+	}
+	if err := compareRequest.Target.validate(); err != nil {
+		return nil, err
+	}
+	if err := compareRequest.Reference.validate(); err != nil {
+		return nil, err
+	}
+	if compareRequest.Limit <= 0 {
+		compareRequest.Limit = defaultKeywordLimit
+	}
+
+	analyzer, err := newAnalyzer(compareRequest.analysisOptions)
+	if err != nil {
+		return nil, err
+	}
+	analyses, err := analyzeFiles(analyzer)
+	if err != nil {
+		return nil, err
+	}
+
+	restOfCorpus := compareRequest.Reference.empty()
+	target, reference := corpusSet{Files: []string{}}, corpusSet{Files: []string{}}
+	targetCounts, referenceCounts := make(map[string]int), make(map[string]int)
+	for _, analysis := range analyses {
+		inTarget := compareRequest.Target.matches(analysis.Name)
+		if inTarget {
+			target.Files = append(target.Files, analysis.Name)
+			target.Words += addCounts(targetCounts, analysis.Counts)
+		}
+		if (restOfCorpus && !inTarget) || (!restOfCorpus && compareRequest.Reference.matches(analysis.Name)) {
+			reference.Files = append(reference.Files, analysis.Name)
+			reference.Words += addCounts(referenceCounts, analysis.Counts)
+		}
+	}
+	if target.Words == 0 || reference.Words == 0 {
+		return nil, fmt.Errorf("both target and reference need files with words, target has %v and reference has %v", len(target.Files), len(reference.Files))
+	}
+
+	overused, underused := []keyWord{}, []keyWord{}
+	for word := range mergeKeys(targetCounts, referenceCounts) {
+		a, b := targetCounts[word], referenceCounts[word]
+		kw := keyWord{
+			Word:               word,
+			TargetCount:        a,
+			ReferenceCount:     b,
+			TargetFrequency:    perMillion(a, target.Words),
+			ReferenceFrequency: perMillion(b, reference.Words),
+			LogLikelihood:      logLikelihoodRatio(a, b, target.Words-a, reference.Words-b),
+		}
+		if kw.TargetFrequency > kw.ReferenceFrequency {
+			overused = append(overused, kw)
+		} else if kw.TargetFrequency < kw.ReferenceFrequency {
+			underused = append(underused, kw)
+		}
+	}
+
+	return &compareResponse{
+		Target:    target,
+		Reference: reference,
+		Overused:  topKeyWords(overused, compareRequest.Limit),
+		Underused: topKeyWords(underused, compareRequest.Limit),
+	}, nil
+}
+
+// addCounts is adding the word counts to total and returning the number of words added
+func addCounts(total, counts map[string]int) int {
+	words := 0
+	for word, count := range counts {
+		total[word] += count
+		words += count
+	}
+	return words
+}
+
+// mergeKeys is returning the words of both the maps
+func mergeKeys(a, b map[string]int) map[string]bool {
+	keys := make(map[string]bool, len(a)+len(b))
+	for word := range a {
+		keys[word] = true
+	}
+	for word := range b {
+		keys[word] = true
+	}
+	return keys
+}
+
+// perMillion is the frequency of count in total words per million words
+func perMillion(count, total int) float64 {
+	return float64(count) * 1e6 / float64(total)
+}
+
+// topKeyWords is sorting the words by log-likelihood and keeping the first limit
+// words with the same score are ordered alphabetically
+func topKeyWords(words []keyWord, limit int) []keyWord {
+	sort.Slice(words, func(i, j int) bool {
+		if words[i].LogLikelihood != words[j].LogLikelihood {
+			return words[i].LogLikelihood > words[j].LogLikelihood
+		}
+		return words[i].Word < words[j].Word
+	})
+	if len(words) > limit {
+		words = words[:limit]
+	}
+	return words
+}
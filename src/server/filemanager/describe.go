@@ -0,0 +1,450 @@
+package filemanager
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultTopValues is the number of most frequent values of categorical columns
+const defaultTopValues = 5
+
+// sampleRows is the number of rows the header is detected from
+const sampleRows = 50
+
+// column types, empty is a column with only null values
+const (
+	columnInteger = "integer"
+	columnFloat   = "float"
+	columnBoolean = "boolean"
+	columnDate    = "date"
+	columnString  = "string"
+	columnEmpty   = "empty"
+)
+
+// delimiters are the field delimiters which are detected
+var delimiters = []rune{',', '\t', ';', '|'}
+
+// nullValues are the (lower case) values which are taken as missing
+var nullValues = map[string]bool{"": true, "na": true, "n/a": true, "null": true, "none": true, "nan": true}
+
+// booleanValues are the (lower case) values of boolean columns
+var booleanValues = map[string]bool{"true": true, "false": true, "yes": true, "no": true}
+
+// dateLayouts are the layouts of date columns
+var dateLayouts = []string{
+	"2006-01-02",
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"01/02/2006",
+	"02.01.2006",
+}
+
+// describeRequest is representing the tabular analysis request
+// Delimiter and Header are detected when not given
+type describeRequest struct {
+	Name      string `json:"name"`
+	Delimiter string `json:"delimiter"`
+	Header    *bool  `json:"header"`
+	Top       int    `json:"top"`
+}
+
+// valueCount is representing a value of a column and the rows it is in
+type valueCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// columnStats is representing a column of a table
+// Min, Max and Mean are given for numeric columns and Top for the others
+type columnStats struct {
+	Name     string       `json:"name"`
+	Type     string       `json:"type"`
+	Nulls    int          `json:"nulls"`
+	Distinct int          `json:"distinct"`
+	Min      *float64     `json:"min,omitempty"`
+	Max      *float64     `json:"max,omitempty"`
+	Mean     *float64     `json:"mean,omitempty"`
+	Top      []valueCount `json:"top,omitempty"`
+}
+
+// describeResponse is representing the columns of a csv file
+// RaggedRows are the rows with more or less fields than columns
+type describeResponse struct {
+	Name       string        `json:"name"`
+	Delimiter  string        `json:"delimiter"`
+	Header     bool          `json:"header"`
+	Rows       int           `json:"rows"`
+	RaggedRows int           `json:"ragged_rows"`
+	Columns    []columnStats `json:"columns"`
+}
+
+// columnCounter is collecting the values of a column, a column keeps a
+// type as long as every value which is not null parses as it
+// decimalComma is reading "2,5" as a number, which is done for tables
+// which are not separated by commas
+type columnCounter struct {
+	decimalComma            bool
+	nulls                   int
+	values                  map[string]int
+	integer, float, boolean bool
+	date                    bool
+	sum, min, max           float64
+	numbers                 int
+}
+
+// Describe is returning the column types and statistics of a csv file
+// pii in the most frequent values is redacted when the pii action is redact
+func (fm *fileManager) Describe(r *http.Request) (interface{}, error) {
+	describeRequest := &describeRequest{}
+	if err := decodeBody(r, describeRequest); err != nil {
+		return nil, fmt.Errorf("describe request body decoding failed with %v", err)
+	}
+	if describeRequest.Top <= 0 {
+		describeRequest.Top = defaultTopValues
+	}
+	if describeRequest.Delimiter != "" && len([]rune(describeRequest.Delimiter)) != 1 {
+		return nil, &statusError{code: http.StatusBadRequest, err: fmt.Errorf("delimiter %q has to be a single character", describeRequest.Delimiter)}
+	}
+
+	filePath, err := getFilePath(describeRequest.Name)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := loadMeta(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, &statusError{code: http.StatusNotFound, err: fmt.Errorf("file does not exist %v", describeRequest.Name)}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !meta.Text {
+		return nil, &statusError{code: http.StatusUnsupportedMediaType, err: fmt.Errorf("file %v of type %v is not a table", describeRequest.Name, mediaTypeOf(meta.ContentType))}
+	}
+
+	scanner, err := redactingScanner()
+	if err != nil {
+		return nil, err
+	}
+	file, err := openText(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error while opening file %v with error %v", describeRequest.Name, err)
+	}
+	defer file.Close()
+	response, err := describeTable(describeRequest, bufio.NewReader(file))
+	if err != nil {
+		return nil, err
+	}
+	for _, column := range response.Columns {
+		for i := range column.Top {
+			column.Top[i].Value = scanner.redactText(column.Top[i].Value)
+		}
+	}
+	return response, nil
+}
+
+// describeTable is reading the rows of a table and counting its columns
+func describeTable(request *describeRequest, rdr *bufio.Reader) (*describeResponse, error) {
+	delimiter := []rune(request.Delimiter)
+	if len(delimiter) == 0 {
+		head, _ := rdr.Peek(sniffLength)
+		delimiter = []rune{detectDelimiter(string(head))}
+	}
+
+	reader := csv.NewReader(rdr)
+	reader.Comma = delimiter[0]
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	sample := [][]string{}
+	for len(sample) < sampleRows {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, &statusError{code: http.StatusUnprocessableEntity, err: fmt.Errorf("file %v is not csv, %v", request.Name, err)}
+		}
+		sample = append(sample, record)
+	}
+
+	response := &describeResponse{Name: request.Name, Delimiter: string(delimiter), Columns: []columnStats{}}
+	if len(sample) == 0 {
+		return response, nil
+	}
+	if request.Header != nil {
+		response.Header = *request.Header
+	} else {
+		response.Header = detectHeader(sample)
+	}
+
+	names := []string{}
+	if response.Header {
+		names = sample[0]
+		sample = sample[1:]
+	}
+	width := len(names)
+	for _, record := range sample {
+		if len(record) > width {
+			width = len(record)
+		}
+	}
+	counters := make([]*columnCounter, width)
+	for i := range counters {
+		counters[i] = newColumnCounter()
+		counters[i].decimalComma = delimiter[0] != ','
+	}
+
+	add := func(record []string) {
+		response.Rows++
+		if len(record) != width {
+			response.RaggedRows++
+		}
+		for i, counter := range counters {
+			value := ""
+			if i < len(record) {
+				value = record[i]
+			}
+			counter.add(value)
+		}
+	}
+	for _, record := range sample {
+		add(record)
+	}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, &statusError{code: http.StatusUnprocessableEntity, err: fmt.Errorf("file %v is not csv, %v", request.Name, err)}
+		}
+		add(record)
+	}
+
+	for i, counter := range counters {
+		name := ""
+		if i < len(names) {
+			name = strings.TrimSpace(names[i])
+		}
+		if name == "" {
+			name = "column" + strconv.Itoa(i+1)
+		}
+		response.Columns = append(response.Columns, counter.stats(name, request.Top))
+	}
+	return response, nil
+}
+
+// detectDelimiter is returning the delimiter splitting the first lines of
+// a table into the same number of fields, the one with most fields wins
+func detectDelimiter(head string) rune {
+	lines := strings.Split(head, "\n")
+	if len(lines) > 1 {
+		// the last line may be cut
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) > sampleRows {
+		lines = lines[:sampleRows]
+	}
+
+	best, bestFields := ',', 1
+	for _, delimiter := range delimiters {
+		reader := csv.NewReader(strings.NewReader(strings.Join(lines, "\n")))
+		reader.Comma = delimiter
+		reader.FieldsPerRecord = -1
+		reader.LazyQuotes = true
+		records, err := reader.ReadAll()
+		if err != nil || len(records) == 0 {
+			continue
+		}
+		fields := len(records[0])
+		for _, record := range records {
+			if len(record) != fields {
+				fields = 0
+				break
+			}
+		}
+		if fields > bestFields {
+			best, bestFields = delimiter, fields
+		}
+	}
+	return best
+}
+
+// detectHeader is checking whether the first row of a table is a header
+// it is when one of its values does not have the type of the column or,
+// for tables of text only, when its values are unique and not in the rows
+func detectHeader(sample [][]string) bool {
+	if len(sample) < 2 {
+		return false
+	}
+	first := sample[0]
+	seen := make(map[string]bool, len(first))
+	for _, name := range first {
+		if nullValues[strings.ToLower(strings.TrimSpace(name))] || seen[name] {
+			return false
+		}
+		seen[name] = true
+	}
+
+	counters := make([]*columnCounter, len(first))
+	typed := false
+	for i, name := range first {
+		counters[i] = newColumnCounter()
+		for _, record := range sample[1:] {
+			if i < len(record) {
+				counters[i].add(record[i])
+			}
+		}
+		columnType := counters[i].columnType()
+		nameType := valueType(name)
+		if (columnType == columnFloat || columnType == columnInteger) && (nameType == columnFloat || nameType == columnInteger) {
+			nameType = columnType
+		}
+		if columnType != columnString && columnType != columnEmpty {
+			if nameType != columnType {
+				return true
+			}
+			typed = true
+		}
+	}
+	if typed {
+		return false
+	}
+	for i, name := range first {
+		if counters[i].values[strings.TrimSpace(name)] > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// newColumnCounter is returning a counter of a column which may still be of every type
+func newColumnCounter() *columnCounter {
+	return &columnCounter{
+		values:  make(map[string]int),
+		integer: true,
+		float:   true,
+		boolean: true,
+		date:    true,
+		min:     math.Inf(1),
+		max:     math.Inf(-1),
+	}
+}
+
+// add is counting a value of the column
+func (c *columnCounter) add(value string) {
+	value = strings.TrimSpace(value)
+	if nullValues[strings.ToLower(value)] {
+		c.nulls++
+		return
+	}
+	c.values[value]++
+
+	if c.integer {
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			c.integer = false
+		}
+	}
+	if c.float {
+		number := value
+		if c.decimalComma && strings.Count(number, ",") == 1 && !strings.Contains(number, ".") {
+			number = strings.Replace(number, ",", ".", 1)
+		}
+		if f, err := strconv.ParseFloat(number, 64); err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+			c.float = false
+		} else {
+			c.numbers++
+			c.sum += f
+			c.min = math.Min(c.min, f)
+			c.max = math.Max(c.max, f)
+		}
+	}
+	if c.boolean && !booleanValues[strings.ToLower(value)] {
+		c.boolean = false
+	}
+	if c.date && !isDate(value) {
+		c.date = false
+	}
+}
+
+// columnType is returning the most specific type of all the values
+func (c *columnCounter) columnType() string {
+	switch {
+	case len(c.values) == 0:
+		return columnEmpty
+	case c.integer:
+		return columnInteger
+	case c.float:
+		return columnFloat
+	case c.boolean:
+		return columnBoolean
+	case c.date:
+		return columnDate
+	}
+	return columnString
+}
+
+// stats is returning the statistics of the column
+func (c *columnCounter) stats(name string, top int) columnStats {
+	stats := columnStats{
+		Name:     name,
+		Type:     c.columnType(),
+		Nulls:    c.nulls,
+		Distinct: len(c.values),
+	}
+	switch stats.Type {
+	case columnInteger, columnFloat:
+		mean := c.sum / float64(c.numbers)
+		stats.Min, stats.Max, stats.Mean = &c.min, &c.max, &mean
+	case columnEmpty:
+	default:
+		stats.Top = topValues(c.values, top)
+	}
+	return stats
+}
+
+// topValues is returning the most frequent values, equal counts are sorted by value
+func topValues(values map[string]int, top int) []valueCount {
+	counts := make([]valueCount, 0, len(values))
+	for value, count := range values {
+		counts = append(counts, valueCount{Value: value, Count: count})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Value < counts[j].Value
+	})
+	if len(counts) > top {
+		counts = counts[:top]
+	}
+	return counts
+}
+
+// valueType is returning the type of a single value
+func valueType(value string) string {
+	counter := newColumnCounter()
+	counter.add(value)
+	return counter.columnType()
+}
+
+// isDate is checking whether value is a date of one of the date layouts
+func isDate(value string) bool {
+	for _, layout := range dateLayouts {
+		if _, err := time.Parse(layout, value); err == nil {
+			return true
+		}
+	}
+	return false
+}
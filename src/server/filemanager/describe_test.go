@@ -0,0 +1,81 @@
+package filemanager
+
+import (
+	"bufio"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_detectDelimiter(t *testing.T) {
+	tests := []struct {
+		name string
+		head string
+		want rune
+	}{
+		{name: "comma", head: "a,b,c\n1,2,3\n", want: ','},
+		{name: "tab", head: "a\tb\n1\t2,5\n", want: '\t'},
+		{name: "semicolon with decimal commas", head: "a;b\n1,5;2\n3;4,25\n", want: ';'},
+		{name: "quoted delimiters", head: "name|note\n\"x\"|\"a, b, c\"\n", want: '|'},
+		{name: "single column", head: "words\nhello\n", want: ','},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectDelimiter(tt.head); got != tt.want {
+				t.Errorf("detectDelimiter() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_detectHeader(t *testing.T) {
+	tests := []struct {
+		name   string
+		sample [][]string
+		want   bool
+	}{
+		{name: "typed columns", sample: [][]string{{"id", "price"}, {"1", "2.5"}, {"2", "3"}}, want: true},
+		{name: "numbers only", sample: [][]string{{"1", "2.5"}, {"2", "3"}}, want: false},
+		{name: "text columns", sample: [][]string{{"city", "country"}, {"Paris", "France"}, {"Lyon", "France"}}, want: true},
+		{name: "repeated value", sample: [][]string{{"France", "Paris"}, {"France", "Lyon"}}, want: false},
+		{name: "empty name", sample: [][]string{{"id", ""}, {"1", "x"}}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectHeader(tt.sample); got != tt.want {
+				t.Errorf("detectHeader() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_describeTable(t *testing.T) {
+	table := "id;price;in_stock;added;color\n1;2,5;yes;2023-01-02;red\n2;NA;no;2023-02-03;blue\n3;4;yes;2023-03-04;red\n4;1.5;no;;\n"
+	got, err := describeTable(&describeRequest{Name: "t.csv", Top: 2}, bufio.NewReader(strings.NewReader(table)))
+	if err != nil {
+		t.Fatalf("describeTable() error = %v", err)
+	}
+	float := func(f float64) *float64 { return &f }
+	want := &describeResponse{
+		Name:      "t.csv",
+		Delimiter: ";",
+		Header:    true,
+		Rows:      4,
+		Columns: []columnStats{
+			{Name: "id", Type: "integer", Distinct: 4, Min: float(1), Max: float(4), Mean: float(2.5)},
+			{Name: "price", Type: "float", Nulls: 1, Distinct: 3, Min: float(1.5), Max: float(4), Mean: float(8.0 / 3)},
+			{Name: "in_stock", Type: "boolean", Distinct: 2, Top: []valueCount{{Value: "no", Count: 2}, {Value: "yes", Count: 2}}},
+			{Name: "added", Type: "date", Nulls: 1, Distinct: 3, Top: []valueCount{{Value: "2023-01-02", Count: 1}, {Value: "2023-02-03", Count: 1}}},
+			{Name: "color", Type: "string", Nulls: 1, Distinct: 2, Top: []valueCount{{Value: "red", Count: 2}, {Value: "blue", Count: 1}}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("describeTable() = %+v, want %+v", got, want)
+	}
+
+	noHeader := false
+	got, err = describeTable(&describeRequest{Header: &noHeader, Top: 1}, bufio.NewReader(strings.NewReader("a,b\nc\n")))
+	if err != nil || got.Rows != 2 || got.RaggedRows != 1 || got.Columns[1].Name != "column2" || got.Columns[1].Nulls != 1 {
+		t.Errorf("describeTable() = %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,461 @@
+package filemanager
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// defaultDiffContext is the number of unchanged lines around the changes of a hunk
+const defaultDiffContext = 3
+
+// maxDiffSize is the size of the largest file which is compared, both
+// files are held in memory
+const maxDiffSize = 32 << 20
+
+// diff line types
+const (
+	diffContext = "context"
+	diffAdded   = "added"
+	diffRemoved = "removed"
+)
+
+// diffRequest is representing the diff request, the stored file Name is
+// compared with the stored file Other or, when Other is not given, with
+// Content which is the replacement about to be uploaded
+// Label is naming Content in the unified diff, Context is the number of
+// unchanged lines around the changes (3 when not given)
+type diffRequest struct {
+	Name    string `json:"name"`
+	Other   string `json:"other"`
+	Content []byte `json:"content"`
+	Label   string `json:"label"`
+	Context *int   `json:"context"`
+}
+
+// diffLine is representing a line of a hunk, OldLine and NewLine are the
+// numbers of the line in the files it is in
+// NoNewline is set for the last line of a file without line ending
+type diffLine struct {
+	Type      string `json:"type"`
+	Text      string `json:"text"`
+	OldLine   int    `json:"old_line,omitempty"`
+	NewLine   int    `json:"new_line,omitempty"`
+	NoNewline bool   `json:"no_newline,omitempty"`
+}
+
+// diffHunk is representing changed lines with the lines around them
+type diffHunk struct {
+	OldStart int        `json:"old_start"`
+	OldLines int        `json:"old_lines"`
+	NewStart int        `json:"new_start"`
+	NewLines int        `json:"new_lines"`
+	Lines    []diffLine `json:"lines"`
+}
+
+// diffStats is representing the number of added and removed lines
+type diffStats struct {
+	Added   int `json:"added"`
+	Removed int `json:"removed"`
+	Hunks   int `json:"hunks"`
+}
+
+// diffResponse is representing the differences of two files as a unified
+// diff and as hunks, both are empty when the files are the same
+type diffResponse struct {
+	From    string     `json:"from"`
+	To      string     `json:"to"`
+	Unified string     `json:"unified"`
+	Hunks   []diffHunk `json:"hunks"`
+	Stats   diffStats  `json:"stats"`
+}
+
+// Diff is comparing a stored file with another stored file or with the
+// content it would be replaced with, line by line
+// pii is redacted when the pii action is redact
+func (fm *fileManager) Diff(r *http.Request) (interface{}, error) {
+	diffRequest := &diffRequest{}
+	if err := decodeBody(r, diffRequest); err != nil {
+		return nil, fmt.Errorf("diff request body decoding failed with %v", err)
+	}
+	context := defaultDiffContext
+	if diffRequest.Context != nil {
+		context = *diffRequest.Context
+	}
+	if context < 0 {
+		return nil, &statusError{code: http.StatusBadRequest, err: fmt.Errorf("invalid context %v", context)}
+	}
+
+	from, err := storedText(diffRequest.Name)
+	if err != nil {
+		return nil, err
+	}
+	response := &diffResponse{From: diffRequest.Name, To: diffRequest.Other}
+	var to string
+	if diffRequest.Other != "" {
+		if to, err = storedText(diffRequest.Other); err != nil {
+			return nil, err
+		}
+	} else {
+		if len(diffRequest.Content) > maxDiffSize {
+			return nil, &statusError{code: http.StatusRequestEntityTooLarge, err: fmt.Errorf("content is larger than %v bytes", maxDiffSize)}
+		}
+		response.To = diffRequest.Label
+		if response.To == "" {
+			response.To = diffRequest.Name + " (uploaded)"
+		}
+		if to, err = comparedText(diffRequest.Name, sniffMeta(diffRequest.Name, diffRequest.Content), diffRequest.Content); err != nil {
+			return nil, err
+		}
+	}
+
+	// both sides are redacted so that redacted pii is not a change
+	scanner, err := redactingScanner()
+	if err != nil {
+		return nil, err
+	}
+	from, to = scanner.redactText(from), scanner.redactText(to)
+
+	response.Hunks = diffHunks(splitLines(from), splitLines(to), context)
+	for _, hunk := range response.Hunks {
+		for _, line := range hunk.Lines {
+			switch line.Type {
+			case diffAdded:
+				response.Stats.Added++
+			case diffRemoved:
+				response.Stats.Removed++
+			}
+		}
+	}
+	response.Stats.Hunks = len(response.Hunks)
+	response.Unified = unifiedDiff(response.From, response.To, response.Hunks)
+	return response, nil
+}
+
+// storedText is returning the text of a stored file which is compared
+func storedText(name string) (string, error) {
+	fPath, err := getFilePath(name)
+	if err != nil {
+		return "", err
+	}
+	meta, err := loadMeta(fPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", &statusError{code: http.StatusNotFound, err: fmt.Errorf("file does not exist %v", name)}
+	}
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(fPath)
+	if err != nil {
+		return "", err
+	}
+	if info.Size() > maxDiffSize {
+		return "", &statusError{code: http.StatusRequestEntityTooLarge, err: fmt.Errorf("file %v is larger than %v bytes", name, maxDiffSize)}
+	}
+	content, err := ioutil.ReadFile(fPath)
+	if err != nil {
+		return "", fmt.Errorf("error while reading file %v with error %v", name, err)
+	}
+	return comparedText(name, meta, content)
+}
+
+// comparedText is returning the text of a file which is compared, text
+// is decoded to utf-8 and the text of archived documents is extracted
+// html and markdown are compared as they are written
+func comparedText(name string, meta *fileMeta, content []byte) (string, error) {
+	if meta.Text {
+		return decodeText(content, meta.Encoding)
+	}
+	if e, ok := extractorFor(meta.ContentType); ok {
+		text, err := e.extract(content)
+		if err != nil {
+			return "", &statusError{code: http.StatusUnprocessableEntity, err: fmt.Errorf("extracting the text of %v failed with %v", name, err)}
+		}
+		return text, nil
+	}
+	return "", &statusError{code: http.StatusUnsupportedMediaType, err: fmt.Errorf("file %v of type %v can not be compared line by line", name, meta.ContentType)}
+}
+
+// splitLines is splitting text into lines which keep their line ending so
+// that a last line without line ending differs from the same line with one
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffHunks is returning the hunks of the changes turning a into b with
+// context unchanged lines around them, hunks which context lines would
+// overlap or touch are merged as diff -u does
+func diffHunks(a, b []string, context int) []diffHunk {
+	removed, added := diffLines(a, b)
+
+	lines := make([]diffLine, 0, len(a)+len(b))
+	for i, j := 0, 0; i < len(a) || j < len(b); {
+		switch {
+		case i < len(a) && removed[i]:
+			lines = append(lines, newDiffLine(diffRemoved, a[i], i+1, 0))
+			i++
+		case j < len(b) && added[j]:
+			lines = append(lines, newDiffLine(diffAdded, b[j], 0, j+1))
+			j++
+		default:
+			lines = append(lines, newDiffLine(diffContext, a[i], i+1, j+1))
+			i++
+			j++
+		}
+	}
+
+	hunks := []diffHunk{}
+	for i := 0; i < len(lines); {
+		if lines[i].Type == diffContext {
+			i++
+			continue
+		}
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for j := i; j < len(lines) && j-end <= 2*context+1; j++ {
+			if lines[j].Type != diffContext {
+				end = j
+			}
+		}
+		stop := end + context + 1
+		if stop > len(lines) {
+			stop = len(lines)
+		}
+		hunks = append(hunks, newDiffHunk(lines[start:stop], lines[:start]))
+		i = stop
+	}
+	return hunks
+}
+
+// newDiffLine is returning a line of a hunk without its line ending
+func newDiffLine(lineType, line string, oldLine, newLine int) diffLine {
+	return diffLine{
+		Type:      lineType,
+		Text:      strings.TrimSuffix(line, "\n"),
+		OldLine:   oldLine,
+		NewLine:   newLine,
+		NoNewline: !strings.HasSuffix(line, "\n"),
+	}
+}
+
+// newDiffHunk is returning a hunk of lines, the lines before it give where it starts
+// a hunk without lines of a file starts after the line before it as in diff -u
+func newDiffHunk(lines, before []diffLine) diffHunk {
+	hunk := diffHunk{Lines: lines}
+	for _, line := range before {
+		if line.OldLine > 0 {
+			hunk.OldStart++
+		}
+		if line.NewLine > 0 {
+			hunk.NewStart++
+		}
+	}
+	for _, line := range lines {
+		if line.OldLine > 0 {
+			hunk.OldLines++
+		}
+		if line.NewLine > 0 {
+			hunk.NewLines++
+		}
+	}
+	if hunk.OldLines > 0 {
+		hunk.OldStart++
+	}
+	if hunk.NewLines > 0 {
+		hunk.NewStart++
+	}
+	return hunk
+}
+
+// unifiedDiff is writing hunks in the unified format of diff -u
+func unifiedDiff(from, to string, hunks []diffHunk) string {
+	if len(hunks) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %v\n+++ %v\n", from, to)
+	for _, hunk := range hunks {
+		fmt.Fprintf(&b, "@@ -%v +%v @@\n", hunkRange(hunk.OldStart, hunk.OldLines), hunkRange(hunk.NewStart, hunk.NewLines))
+		for _, line := range hunk.Lines {
+			switch line.Type {
+			case diffAdded:
+				b.WriteString("+")
+			case diffRemoved:
+				b.WriteString("-")
+			default:
+				b.WriteString(" ")
+			}
+			b.WriteString(line.Text)
+			b.WriteString("\n")
+			if line.NoNewline {
+				b.WriteString("\\ No newline at end of file\n")
+			}
+		}
+	}
+	return b.String()
+}
+
+// hunkRange is returning the range of a hunk header, the length is left
+// out when it is 1
+func hunkRange(start, lines int) string {
+	if lines == 1 {
+		return fmt.Sprint(start)
+	}
+	return fmt.Sprintf("%v,%v", start, lines)
+}
+
+// diffLines is marking the lines of a which are removed and the lines of b
+// which are added by a shortest edit script, found by the linear space
+// variant of the Myers difference algorithm
+func diffLines(a, b []string) ([]bool, []bool) {
+	ids := make(map[string]int)
+	d := &differ{
+		a:       lineIDs(a, ids),
+		b:       lineIDs(b, ids),
+		removed: make([]bool, len(a)),
+		added:   make([]bool, len(b)),
+	}
+	d.compare(0, len(a), 0, len(b))
+	return d.removed, d.added
+}
+
+// lineIDs is numbering lines so that equal lines have the same number
+func lineIDs(lines []string, ids map[string]int) []int {
+	numbers := make([]int, len(lines))
+	for i, line := range lines {
+		id, ok := ids[line]
+		if !ok {
+			id = len(ids)
+			ids[line] = id
+		}
+		numbers[i] = id
+	}
+	return numbers
+}
+
+// differ is comparing two sequences of line numbers
+type differ struct {
+	a, b           []int
+	removed, added []bool
+}
+
+// compare is marking the differences of a[aLo:aHi] and b[bLo:bHi], the
+// sequences are split at the middle of a shortest edit script
+func (d *differ) compare(aLo, aHi, bLo, bHi int) {
+	for aLo < aHi && bLo < bHi && d.a[aLo] == d.b[bLo] {
+		aLo++
+		bLo++
+	}
+	for aLo < aHi && bLo < bHi && d.a[aHi-1] == d.b[bHi-1] {
+		aHi--
+		bHi--
+	}
+	switch {
+	case aLo == aHi:
+		for j := bLo; j < bHi; j++ {
+			d.added[j] = true
+		}
+	case bLo == bHi:
+		for i := aLo; i < aHi; i++ {
+			d.removed[i] = true
+		}
+	default:
+		x, y, ok := d.bisect(aLo, aHi, bLo, bHi)
+		if !ok {
+			for i := aLo; i < aHi; i++ {
+				d.removed[i] = true
+			}
+			for j := bLo; j < bHi; j++ {
+				d.added[j] = true
+			}
+			return
+		}
+		d.compare(aLo, x, bLo, y)
+		d.compare(x, aHi, y, bHi)
+	}
+}
+
+// bisect is returning where the forward and the backward search for a
+// shortest edit script meet, it is not ok when the sequences have nothing
+// in common
+func (d *differ) bisect(aLo, aHi, bLo, bHi int) (int, int, bool) {
+	n, m := aHi-aLo, bHi-bLo
+	maxD := (n + m + 1) / 2
+	offset := maxD
+	forward := make([]int, 2*maxD+2)
+	backward := make([]int, 2*maxD+2)
+	for i := range forward {
+		forward[i], backward[i] = -1, -1
+	}
+	forward[offset+1], backward[offset+1] = 0, 0
+	delta := n - m
+	odd := delta%2 != 0
+
+	// diagonals which left the edit graph are not searched again
+	fStart, fEnd, bStart, bEnd := 0, 0, 0, 0
+	for step := 0; step < maxD; step++ {
+		for k := -step + fStart; k <= step-fEnd; k += 2 {
+			var x int
+			if k == -step || (k != step && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && d.a[aLo+x] == d.b[bLo+y] {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+			switch {
+			case x > n:
+				fEnd += 2
+			case y > m:
+				fStart += 2
+			case odd:
+				c := offset + delta - k
+				if c >= 0 && c < len(backward) && backward[c] != -1 && x >= n-backward[c] {
+					return aLo + x, bLo + y, true
+				}
+			}
+		}
+
+		for c := -step + bStart; c <= step-bEnd; c += 2 {
+			var x int
+			if c == -step || (c != step && backward[offset+c-1] < backward[offset+c+1]) {
+				x = backward[offset+c+1]
+			} else {
+				x = backward[offset+c-1] + 1
+			}
+			y := x - c
+			for x < n && y < m && d.a[aHi-1-x] == d.b[bHi-1-y] {
+				x++
+				y++
+			}
+			backward[offset+c] = x
+			switch {
+			case x > n:
+				bEnd += 2
+			case y > m:
+				bStart += 2
+			case !odd:
+				k := offset + delta - c
+				if k >= 0 && k < len(forward) && forward[k] != -1 && forward[k] >= n-x {
+					fx := forward[k]
+					return aLo + fx, bLo + fx - (k - offset), true
+				}
+			}
+		}
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,82 @@
+package filemanager
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_diffLines(t *testing.T) {
+	tests := []struct {
+		name        string
+		a, b        []string
+		wantRemoved []bool
+		wantAdded   []bool
+	}{
+		{name: "same", a: []string{"a", "b"}, b: []string{"a", "b"}, wantRemoved: []bool{false, false}, wantAdded: []bool{false, false}},
+		{name: "changed line", a: []string{"a", "b", "c"}, b: []string{"a", "x", "c"}, wantRemoved: []bool{false, true, false}, wantAdded: []bool{false, true, false}},
+		{name: "moved line", a: []string{"a", "b", "c", "d"}, b: []string{"b", "c", "a", "d"}, wantRemoved: []bool{true, false, false, false}, wantAdded: []bool{false, false, true, false}},
+		{name: "nothing in common", a: []string{"a", "b"}, b: []string{"c"}, wantRemoved: []bool{true, true}, wantAdded: []bool{true}},
+		{name: "empty", a: []string{}, b: []string{"a"}, wantRemoved: []bool{}, wantAdded: []bool{true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			removed, added := diffLines(tt.a, tt.b)
+			if !reflect.DeepEqual(removed, tt.wantRemoved) || !reflect.DeepEqual(added, tt.wantAdded) {
+				t.Errorf("diffLines() = %v, %v, want %v, %v", removed, added, tt.wantRemoved, tt.wantAdded)
+			}
+		})
+	}
+}
+
+func Test_unifiedDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    string
+		context int
+		want    string
+	}{
+		{name: "separate hunks",
+			a:       "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n",
+			b:       "a\nB\nc\nd\ne\nf\ng\nh\nj\nk",
+			context: 1,
+			want: "--- old\n+++ new\n" +
+				"@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n" +
+				"@@ -8,3 +8,3 @@\n h\n-i\n j\n+k\n\\ No newline at end of file\n",
+		},
+		{name: "merged hunks",
+			a:       "a\nb\nc\nd\ne\n",
+			b:       "a\nB\nc\nD\ne\n",
+			context: 1,
+			want:    "--- old\n+++ new\n@@ -1,5 +1,5 @@\n a\n-b\n+B\n c\n-d\n+D\n e\n",
+		},
+		{name: "hunks 2*context lines apart are merged",
+			a:       "x\n1\n2\n3\n4\n5\n6\ny\n",
+			b:       "X\n1\n2\n3\n4\n5\n6\nY\n",
+			context: 3,
+			want:    "--- old\n+++ new\n@@ -1,8 +1,8 @@\n-x\n+X\n 1\n 2\n 3\n 4\n 5\n 6\n-y\n+Y\n",
+		},
+		{name: "hunks 2*context+1 lines apart are not merged",
+			a:       "x\n1\n2\n3\n4\n5\n6\n7\ny\n",
+			b:       "X\n1\n2\n3\n4\n5\n6\n7\nY\n",
+			context: 3,
+			want: "--- old\n+++ new\n" +
+				"@@ -1,4 +1,4 @@\n-x\n+X\n 1\n 2\n 3\n" +
+				"@@ -6,4 +6,4 @@\n 5\n 6\n 7\n-y\n+Y\n",
+		},
+		{name: "new file", a: "", b: "x\ny\n", context: 3, want: "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+x\n+y\n"},
+		{name: "line ending added",
+			a:       "a\nb\nc",
+			b:       "a\nb\nc\n",
+			context: 3,
+			want:    "--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n b\n-c\n\\ No newline at end of file\n+c\n",
+		},
+		{name: "same", a: "a\n", b: "a\n", context: 3, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unifiedDiff("old", "new", diffHunks(splitLines(tt.a), splitLines(tt.b), tt.context)); got != tt.want {
+				t.Errorf("unifiedDiff() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
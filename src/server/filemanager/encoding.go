@@ -0,0 +1,380 @@
+package filemanager
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// character encodings of text files, files which are neither utf-8 nor
+// utf-16 are taken as latin-1 as every byte is a latin-1 character
+const (
+	encodingUTF8    = "utf-8"
+	encodingUTF16LE = "utf-16le"
+	encodingUTF16BE = "utf-16be"
+	encodingLatin1  = "iso-8859-1"
+)
+
+// line endings of text files, mixed is a file with more than one kind
+const (
+	lineEndingLF    = "lf"
+	lineEndingCRLF  = "crlf"
+	lineEndingCR    = "cr"
+	lineEndingMixed = "mixed"
+)
+
+// utf16ZeroShare is the part of the high bytes which have to be zero for a
+// file without byte order mark to be utf-16, which is true for latin text
+const utf16ZeroShare = 0.4
+
+// byte order marks of the encodings
+var byteOrderMarks = []struct {
+	encoding string
+	bom      []byte
+}{
+	{encoding: encodingUTF8, bom: []byte{0xEF, 0xBB, 0xBF}},
+	{encoding: encodingUTF16LE, bom: []byte{0xFF, 0xFE}},
+	{encoding: encodingUTF16BE, bom: []byte{0xFE, 0xFF}},
+}
+
+// detectEncoding is returning the encoding of the beginning of a file and
+// the length of its byte order mark
+// truncated tells that head is cut from a longer content so that a
+// character cut at the end is not taken as invalid utf-8
+func detectEncoding(head []byte, truncated bool) (string, int) {
+	for _, mark := range byteOrderMarks {
+		if bytes.HasPrefix(head, mark.bom) {
+			return mark.encoding, len(mark.bom)
+		}
+	}
+	if encoding := detectUTF16(head); encoding != "" {
+		return encoding, 0
+	}
+	if validUTF8(head, truncated) {
+		return encodingUTF8, 0
+	}
+	return encodingLatin1, 0
+}
+
+// detectUTF16 is recognising utf-16 without byte order mark by the zero
+// high bytes of latin characters, which are the odd bytes of little endian
+func detectUTF16(head []byte) string {
+	pairs := len(head) / 2
+	if pairs < 2 {
+		return ""
+	}
+	even, odd := 0, 0
+	for i := 0; i+1 < len(head); i += 2 {
+		if head[i] == 0 {
+			even++
+		}
+		if head[i+1] == 0 {
+			odd++
+		}
+	}
+	switch {
+	case float64(odd) >= utf16ZeroShare*float64(pairs) && even == 0:
+		return encodingUTF16LE
+	case float64(even) >= utf16ZeroShare*float64(pairs) && odd == 0:
+		return encodingUTF16BE
+	}
+	return ""
+}
+
+// validUTF8 is checking whether b is utf-8, an incomplete character at the
+// end is valid when b is truncated
+func validUTF8(b []byte, truncated bool) bool {
+	for len(b) > 0 {
+		r, size := utf8.DecodeRune(b)
+		if r == utf8.RuneError && size == 1 {
+			return truncated && !utf8.FullRune(b)
+		}
+		b = b[size:]
+	}
+	return true
+}
+
+// detectLineEndings is returning the line endings used by text
+// it is empty when the text has a single line
+func detectLineEndings(text string) string {
+	crlf := strings.Count(text, "\r\n")
+	lf := strings.Count(text, "\n") - crlf
+	cr := strings.Count(text, "\r") - crlf
+	kinds := map[string]int{lineEndingLF: lf, lineEndingCRLF: crlf, lineEndingCR: cr}
+	found := ""
+	for kind, count := range kinds {
+		if count == 0 {
+			continue
+		}
+		if found != "" {
+			return lineEndingMixed
+		}
+		found = kind
+	}
+	return found
+}
+
+// normalizeLineEndings is replacing all the line endings of text with lf or crlf
+func normalizeLineEndings(text, lineEnding string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	if lineEnding == lineEndingCRLF {
+		text = strings.ReplaceAll(text, "\n", "\r\n")
+	}
+	return text
+}
+
+// decodeText is converting content in an encoding to utf-8 without byte order mark
+func decodeText(content []byte, encoding string) (string, error) {
+	text, err := ioutil.ReadAll(newDecoder(bytes.NewReader(content), encoding))
+	return string(text), err
+}
+
+// encodeText is converting utf-8 text to an encoding, the byte order mark
+// is written when bom is set
+func encodeText(text, encoding string, bom bool) []byte {
+	var b bytes.Buffer
+	if bom {
+		for _, mark := range byteOrderMarks {
+			if mark.encoding == encoding {
+				b.Write(mark.bom)
+			}
+		}
+	}
+	switch encoding {
+	case encodingUTF16LE, encodingUTF16BE:
+		for _, unit := range utf16.Encode([]rune(text)) {
+			if encoding == encodingUTF16LE {
+				b.WriteByte(byte(unit))
+				b.WriteByte(byte(unit >> 8))
+			} else {
+				b.WriteByte(byte(unit >> 8))
+				b.WriteByte(byte(unit))
+			}
+		}
+	case encodingLatin1:
+		for _, r := range text {
+			if r > 0xFF {
+				r = '?'
+			}
+			b.WriteByte(byte(r))
+		}
+	default:
+		b.WriteString(text)
+	}
+	return b.Bytes()
+}
+
+// decoder is reading text in an encoding as utf-8
+// the byte order mark is skipped
+type decoder struct {
+	r        *bufio.Reader
+	encoding string
+	pending  []byte
+}
+
+// newDecoder is returning a reader of r as utf-8
+func newDecoder(r io.Reader, encoding string) io.Reader {
+	d := &decoder{r: bufio.NewReader(r), encoding: encoding}
+	for _, mark := range byteOrderMarks {
+		if mark.encoding != encoding {
+			continue
+		}
+		if head, err := d.r.Peek(len(mark.bom)); err == nil && bytes.Equal(head, mark.bom) {
+			d.r.Discard(len(mark.bom))
+		}
+	}
+	return d
+}
+
+// Read is decoding characters until p is full
+func (d *decoder) Read(p []byte) (int, error) {
+	if d.encoding == "" || d.encoding == encodingUTF8 {
+		return d.r.Read(p)
+	}
+
+	n := copy(p, d.pending)
+	d.pending = d.pending[n:]
+	var buf [utf8.UTFMax]byte
+	for n < len(p) {
+		r, err := d.next()
+		if err != nil {
+			if n > 0 && err == io.EOF {
+				return n, nil
+			}
+			return n, err
+		}
+		size := utf8.EncodeRune(buf[:], r)
+		copied := copy(p[n:], buf[:size])
+		d.pending = append(d.pending, buf[copied:size]...)
+		n += copied
+	}
+	return n, nil
+}
+
+// next is decoding the next character
+func (d *decoder) next() (rune, error) {
+	if d.encoding == encodingLatin1 {
+		b, err := d.r.ReadByte()
+		return rune(b), err
+	}
+
+	unit, err := d.nextUnit()
+	if err != nil {
+		return 0, err
+	}
+	if !utf16.IsSurrogate(rune(unit)) {
+		return rune(unit), nil
+	}
+	low, err := d.nextUnit()
+	if err == io.EOF {
+		return utf8.RuneError, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return utf16.DecodeRune(rune(unit), rune(low)), nil
+}
+
+// nextUnit is reading the next 16 bit unit of utf-16
+// a single byte left at the end is an invalid character
+func (d *decoder) nextUnit() (uint16, error) {
+	var b [2]byte
+	n, err := io.ReadFull(d.r, b[:])
+	if err == io.ErrUnexpectedEOF && n == 1 {
+		return uint16(utf8.RuneError), nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if d.encoding == encodingUTF16BE {
+		return uint16(b[0])<<8 | uint16(b[1]), nil
+	}
+	return uint16(b[1])<<8 | uint16(b[0]), nil
+}
+
+// openText is opening a stored file (fPath as given by readDir) for reading
+// its text as utf-8 whatever the encoding it is stored in
+// the text of documents is extracted
+func openText(fPath string) (io.ReadCloser, error) {
+	meta, err := loadMeta(fPath)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(fPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var rc io.ReadCloser = file
+	if meta.Text && meta.Encoding != "" && (meta.Encoding != encodingUTF8 || meta.BOM) {
+		rc = &decodedFile{Reader: newDecoder(file, meta.Encoding), file: file}
+	}
+	e, ok := extractorFor(meta.ContentType)
+	if !ok {
+		return rc, nil
+	}
+
+	defer rc.Close()
+	content, err := ioutil.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+	text, err := e.extract(content)
+	if err != nil {
+		return nil, fmt.Errorf("extracting the text of %v failed with %v", relativeName(fPath), err)
+	}
+	return ioutil.NopCloser(strings.NewReader(text)), nil
+}
+
+// readText is reading the text of a stored file as utf-8
+func readText(fPath string) ([]byte, error) {
+	file, err := openText(fPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ioutil.ReadAll(file)
+}
+
+// decodedFile is reading a file through a decoder
+type decodedFile struct {
+	io.Reader
+	file *os.File
+}
+
+// Close is closing the file
+func (f *decodedFile) Close() error {
+	return f.file.Close()
+}
+
+// normalizeUpload is applying the encoding and line ending options of
+// uploaded files, it is returning the encodings of converted files
+func normalizeUpload(files []file) (map[string]string, error) {
+	converted := make(map[string]string)
+	for i := range files {
+		f := &files[i]
+		switch f.LineEndings {
+		case "", lineEndingLF, lineEndingCRLF:
+		default:
+			return nil, &statusError{
+				code: http.StatusBadRequest,
+				err:  fmt.Errorf("invalid line endings %v of %v, line endings have to be %v or %v", f.LineEndings, f.Name, lineEndingLF, lineEndingCRLF),
+			}
+		}
+		if !f.ToUTF8 && f.LineEndings == "" {
+			continue
+		}
+		meta := sniffMeta(f.Name, f.Content)
+		if !meta.Text {
+			continue
+		}
+
+		text, err := decodeText(f.Content, meta.Encoding)
+		if err != nil {
+			return nil, fmt.Errorf("decoding %v from %v failed with %v", f.Name, meta.Encoding, err)
+		}
+		if f.LineEndings != "" {
+			text = normalizeLineEndings(text, f.LineEndings)
+		}
+		if f.ToUTF8 {
+			f.Content = []byte(text)
+			if meta.Encoding != encodingUTF8 || meta.BOM {
+				converted[f.Name] = meta.Encoding
+			}
+			continue
+		}
+		f.Content = encodeText(text, meta.Encoding, meta.BOM)
+	}
+	return converted, nil
+}
+
+// uploadedText is returning the content of an uploaded file as utf-8 so
+// that it is scanned whatever its encoding, documents are scanned through
+// their extracted text
+func uploadedText(f file) []byte {
+	meta := sniffMeta(f.Name, f.Content)
+	if !meta.Text {
+		if e, ok := extractorFor(meta.ContentType); ok {
+			if text, err := e.extract(f.Content); err == nil {
+				return []byte(text)
+			}
+		}
+		return f.Content
+	}
+	if meta.Encoding == encodingUTF8 && !meta.BOM {
+		return f.Content
+	}
+	text, err := decodeText(f.Content, meta.Encoding)
+	if err != nil {
+		return f.Content
+	}
+	return []byte(text)
+}
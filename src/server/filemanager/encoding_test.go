@@ -0,0 +1,80 @@
+package filemanager
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_detectEncoding(t *testing.T) {
+	tests := []struct {
+		name      string
+		head      []byte
+		truncated bool
+		want      string
+		wantBOM   int
+	}{
+		{name: "ascii", head: []byte("plain words\n"), want: "utf-8"},
+		{name: "utf-8 bom", head: []byte("\xef\xbb\xbfcaf\xc3\xa9"), want: "utf-8", wantBOM: 3},
+		{name: "utf-16le bom", head: []byte("\xff\xfeh\x00i\x00"), want: "utf-16le", wantBOM: 2},
+		{name: "utf-16be bom", head: []byte("\xfe\xff\x00h\x00i"), want: "utf-16be", wantBOM: 2},
+		{name: "utf-16be", head: []byte("\x00h\x00e\x00y\x00!"), want: "utf-16be"},
+		{name: "latin-1", head: []byte("na\xefve caf\xe9"), want: "iso-8859-1"},
+		{name: "cut utf-8", head: []byte("caf\xc3"), truncated: true, want: "utf-8"},
+		{name: "invalid utf-8 at the end", head: []byte("caf\xc3"), want: "iso-8859-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotBOM := detectEncoding(tt.head, tt.truncated)
+			if got != tt.want || gotBOM != tt.wantBOM {
+				t.Errorf("detectEncoding() = %v, %v, want %v, %v", got, gotBOM, tt.want, tt.wantBOM)
+			}
+		})
+	}
+}
+
+func Test_decodeText(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  []byte
+		encoding string
+		want     string
+	}{
+		{name: "utf-8 bom", content: []byte("\xef\xbb\xbfcaf\xc3\xa9"), encoding: "utf-8", want: "café"},
+		{name: "utf-16le", content: []byte("\xff\xfec\x00a\x00f\x00\xe9\x00"), encoding: "utf-16le", want: "café"},
+		{name: "utf-16be surrogates", content: []byte("\x00a\xd8\x3d\xde\x00"), encoding: "utf-16be", want: "a😀"},
+		{name: "latin-1", content: []byte("caf\xe9"), encoding: "iso-8859-1", want: "café"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeText(tt.content, tt.encoding)
+			if err != nil || got != tt.want {
+				t.Errorf("decodeText() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func Test_encodeText(t *testing.T) {
+	want := []byte("\xff\xfea\x00\r\x00\n\x00")
+	if got := encodeText("a\r\n", "utf-16le", true); !reflect.DeepEqual(got, want) {
+		t.Errorf("encodeText() = %q, want %q", got, want)
+	}
+}
+
+func Test_detectLineEndings(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "one line", want: ""},
+		{text: "a\nb\n", want: "lf"},
+		{text: "a\r\nb\r\n", want: "crlf"},
+		{text: "a\rb", want: "cr"},
+		{text: "a\r\nb\n", want: "mixed"},
+	}
+	for _, tt := range tests {
+		if got := detectLineEndings(tt.text); got != tt.want {
+			t.Errorf("detectLineEndings(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package filemanager
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// entity types
+const (
+	entityURL     = "url"
+	entityEmail   = "email"
+	entityMention = "mention"
+	entityHashtag = "hashtag"
+)
+
+// entityTypes are all the entity types in the order they are returned
+var entityTypes = []string{entityURL, entityEmail, entityMention, entityHashtag}
+
+// entityPattern is matching urls, email addresses, @mentions and #hashtags
+// the earlier alternatives win, so an email address is not read as a mention
+var entityPattern = regexp.MustCompile(`(?i)` +
+	`((?:https?|ftp)://[^\s<>"]+|www\.[^\s<>"]+\.[^\s<>"]+)` +
+	`|([a-z0-9._%+-]+@[a-z0-9-]+(?:\.[a-z0-9-]+)*\.[a-z]{2,})` +
+	`|(@[a-z0-9_]{1,30})` +
+	`|(#[\p{L}\p{N}_]*\p{L}[\p{L}\p{N}_]*)`)
+
+// entity is an url, email address, mention or hashtag found in a text
+type entity struct {
+	Type  string
+	Text  string
+	Start int
+	End   int
+}
+
+// entitiesRequest is representing the entity extraction request
+// Types are the entity types to extract, all of them when empty
+// Limit is the number of entities per type (all when 0), binary files are
+// only read with IncludeBinary
+type entitiesRequest struct {
+	fileFilter
+	Types         []string `json:"types"`
+	Limit         int      `json:"limit"`
+	IncludeBinary bool     `json:"include_binary"`
+}
+
+// entityFile is representing the number of times an entity appears in a file
+type entityFile struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// entityCount is representing an entity with its frequency and files
+type entityCount struct {
+	Text  string       `json:"text"`
+	Count int          `json:"count"`
+	Files []entityFile `json:"files"`
+}
+
+// entityGroup is representing the entities of a type, most frequent first
+// Total is the number of occurrences and Unique the number of entities
+type entityGroup struct {
+	Type     string        `json:"type"`
+	Total    int           `json:"total"`
+	Unique   int           `json:"unique"`
+	Entities []entityCount `json:"entities"`
+}
+
+// entitiesResponse is representing the entities of all the requested types
+type entitiesResponse struct {
+	Types []entityGroup `json:"types"`
+}
+
+// Entities is extracting urls, email addresses, mentions and hashtags of the
+// selected files and returning them with their frequency and files
+func (fm *fileManager) Entities(r *http.Request) (interface{}, error) {
+	entitiesRequest := &entitiesRequest{}
+	if err := decodeBody(r, entitiesRequest); err != nil {
+		return nil, fmt.Errorf("entities request body decoding failed with %v", err)
+	}
+	if len(entitiesRequest.Types) == 0 {
+		entitiesRequest.Types = entityTypes
+	}
+	for _, entityType := range entitiesRequest.Types {
+		if !isEntityType(entityType) {
+			return nil, fmt.Errorf("invalid entity type %v, types are %v", entityType, strings.Join(entityTypes, ", "))
+		}
+	}
+
+	files, err := readDir(filesDir)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading files for entities %v", err)
+	}
+	files, err = entitiesRequest.selectPaths(files)
+	if err != nil {
+		return nil, err
+	}
+	if !entitiesRequest.IncludeBinary {
+		if files, err = textPaths(files); err != nil {
+			return nil, err
+		}
+	}
+
+	// counts is entity type to entity to file to count
+	counts := make(map[string]map[string]map[string]int)
+	for _, fPath := range files {
+		if err := countEntities(fPath, counts); err != nil {
+			return nil, err
+		}
+	}
+
+	entitiesResponse := &entitiesResponse{Types: []entityGroup{}}
+	for _, entityType := range entityTypes {
+		if !containsString(entitiesRequest.Types, entityType) {
+			continue
+		}
+		entitiesResponse.Types = append(entitiesResponse.Types, newEntityGroup(entityType, counts[entityType], entitiesRequest.Limit))
+	}
+	return entitiesResponse, nil
+}
+
+// countEntities is adding the entities of a file to counts
+func countEntities(fPath string, counts map[string]map[string]map[string]int) error {
+	file, err := openText(fPath)
+	if err != nil {
+		return fmt.Errorf("error while opening file %v with error %v", fPath, err)
+	}
+	defer file.Close()
+
+	name := relativeName(fPath)
+	rdr := bufio.NewReader(file)
+	for {
+		line, err := rdr.ReadString('\n')
+		for _, e := range findEntities(line) {
+			if counts[e.Type] == nil {
+				counts[e.Type] = make(map[string]map[string]int)
+			}
+			text := normalizeEntity(e, tokenizerOptions{})
+			if counts[e.Type][text] == nil {
+				counts[e.Type][text] = make(map[string]int)
+			}
+			counts[e.Type][text][name]++
+		}
+		if err != nil {
+			if err != io.EOF {
+				return fmt.Errorf("error while reading from file %v with error %v", fPath, err)
+			}
+			return nil
+		}
+	}
+}
+
+// newEntityGroup is ranking the entities of a type by frequency
+// entities with the same frequency are ordered alphabetically
+func newEntityGroup(entityType string, counts map[string]map[string]int, limit int) entityGroup {
+	group := entityGroup{
+		Type:     entityType,
+		Unique:   len(counts),
+		Entities: make([]entityCount, 0, len(counts)),
+	}
+	for text, files := range counts {
+		ec := entityCount{Text: text, Files: make([]entityFile, 0, len(files))}
+		for name, count := range files {
+			ec.Count += count
+			ec.Files = append(ec.Files, entityFile{Name: name, Count: count})
+		}
+		sort.Slice(ec.Files, func(i, j int) bool {
+			return ec.Files[i].Name < ec.Files[j].Name
+		})
+		group.Total += ec.Count
+		group.Entities = append(group.Entities, ec)
+	}
+	sort.Slice(group.Entities, func(i, j int) bool {
+		if group.Entities[i].Count != group.Entities[j].Count {
+			return group.Entities[i].Count > group.Entities[j].Count
+		}
+		return group.Entities[i].Text < group.Entities[j].Text
+	})
+	if limit > 0 && len(group.Entities) > limit {
+		group.Entities = group.Entities[:limit]
+	}
+	return group
+}
+
+// findEntities is returning the entities of a text in the order they appear
+// mentions and hashtags have to start a word ("a@b" and "c#" are not entities)
+// and punctuation ending a sentence is not part of an url
+func findEntities(text string) []entity {
+	entities := []entity{}
+	for _, loc := range entityPattern.FindAllStringSubmatchIndex(text, -1) {
+		for group, entityType := range entityTypes {
+			start, end := loc[2+2*group], loc[3+2*group]
+			if start < 0 {
+				continue
+			}
+			if entityType == entityURL {
+				end = start + len(trimURL(text[start:end]))
+			}
+			if (entityType == entityMention || entityType == entityHashtag) && !startsWord(text, start) {
+				break
+			}
+			entities = append(entities, entity{Type: entityType, Text: text[start:end], Start: start, End: end})
+			break
+		}
+	}
+	return entities
+}
+
+// trimURL is removing punctuation after an url and closing brackets
+// which are not opened inside the url
+func trimURL(url string) string {
+	for url != "" {
+		last := url[len(url)-1]
+		switch {
+		case strings.IndexByte(".,;:!?'*", last) >= 0:
+		case last == ')' && strings.Count(url, "(") < strings.Count(url, ")"):
+		case last == ']' && strings.Count(url, "[") < strings.Count(url, "]"):
+		default:
+			return url
+		}
+		url = url[:len(url)-1]
+	}
+	return url
+}
+
+// startsWord is checking that the rune before i is not part of a word
+func startsWord(text string, i int) bool {
+	previous, _ := utf8.DecodeLastRuneInString(text[:i])
+	return i == 0 || !(isWordRune(previous) || previous == '_' || previous == '@' || previous == '#')
+}
+
+// normalizeEntity is bringing an entity into its canonical form
+// urls are kept as they are written, other entities are case folded
+func normalizeEntity(e entity, options tokenizerOptions) string {
+	if e.Type == entityURL || options.KeepCase {
+		return e.Text
+	}
+	return strings.Map(unicode.ToLower, e.Text)
+}
+
+// isEntityType is checking whether the name is an entity type
+func isEntityType(name string) bool {
+	return containsString(entityTypes, name)
+}
+
+// containsString is checking whether the list has the value
+func containsString(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// entityTokenizer is keeping the entities of a text as single words
+// and splitting the rest of the text with the words tokenizer
+type entityTokenizer struct {
+	words Tokenizer
+}
+
+// Tokenize is returning the entities and the words around them
+func (et entityTokenizer) Tokenize(text string) []token {
+	tokens := []token{}
+	from := 0
+	for _, e := range findEntities(text) {
+		tokens = append(tokens, et.gap(text, from, e.Start)...)
+		tokens = append(tokens, token{Text: e.Text, Start: e.Start, End: e.End, Entity: e.Type})
+		from = e.End
+	}
+	return append(tokens, et.gap(text, from, len(text))...)
+}
+
+// gap is returning the words of text[from:to] with positions in text
+func (et entityTokenizer) gap(text string, from, to int) []token {
+	tokens := et.words.Tokenize(text[from:to])
+	for i := range tokens {
+		tokens[i].Start += from
+		tokens[i].End += from
+	}
+	return tokens
+}
@@ -0,0 +1,359 @@
+package filemanager
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// content types of the documents stored as zip archives
+const (
+	docxContentType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	odtContentType  = "application/vnd.oasis.opendocument.text"
+)
+
+// maxExtractedSize is the largest document part read from an archive so
+// that a small compressed file can not fill the memory
+const maxExtractedSize = 64 << 20
+
+// maxODTSpaces is the largest number of spaces of a single space element,
+// its count comes from the document
+const maxODTSpaces = 1024
+
+// documentExtensions are content types of zip archives which are documents
+var documentExtensions = map[string]string{
+	".docx": docxContentType,
+	".odt":  odtContentType,
+}
+
+// extractor is pulling the plain text out of a document
+// text documents are given as utf-8, archives as stored
+type extractor struct {
+	name    string
+	extract func(content []byte) (string, error)
+}
+
+// extractors are the extractors of the media types which are not plain text,
+// the text of the other files is analysed as it is
+var extractors = map[string]extractor{
+	"text/html":             {name: "html", extract: extractHTML},
+	"application/xhtml+xml": {name: "html", extract: extractHTML},
+	"text/markdown":         {name: "markdown", extract: extractMarkdown},
+	docxContentType:         {name: "docx", extract: extractDOCX},
+	odtContentType:          {name: "odt", extract: extractODT},
+}
+
+// extractorFor is returning the extractor of a content type
+func extractorFor(contentType string) (extractor, bool) {
+	e, ok := extractors[mediaTypeOf(contentType)]
+	return e, ok
+}
+
+// htmlSkipped are the elements which content is not text
+var htmlSkipped = map[string]bool{
+	"script": true, "style": true, "noscript": true, "template": true, "svg": true,
+}
+
+// htmlBlocks are the elements which start a new line
+var htmlBlocks = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "br": true, "dd": true,
+	"div": true, "dl": true, "dt": true, "figcaption": true, "figure": true, "footer": true,
+	"form": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"header": true, "hr": true, "li": true, "main": true, "nav": true, "ol": true, "p": true,
+	"pre": true, "section": true, "table": true, "td": true, "th": true, "title": true,
+	"tr": true, "ul": true,
+}
+
+// textLines is collecting extracted text line by line, whitespace is
+// collapsed and empty lines are dropped
+type textLines struct {
+	lines []string
+	line  strings.Builder
+}
+
+// write is adding text to the current line
+func (t *textLines) write(text string) {
+	t.line.WriteString(text)
+}
+
+// newLine is ending the current line
+func (t *textLines) newLine() {
+	if line := strings.Join(strings.Fields(t.line.String()), " "); line != "" {
+		t.lines = append(t.lines, line)
+	}
+	t.line.Reset()
+}
+
+// String is returning the lines
+func (t *textLines) String() string {
+	t.newLine()
+	if len(t.lines) == 0 {
+		return ""
+	}
+	return strings.Join(t.lines, "\n") + "\n"
+}
+
+// extractHTML is returning the text of an html page without the tags, the
+// scripts and styles, entities are decoded and block elements are lines
+func extractHTML(content []byte) (string, error) {
+	text := &textLines{}
+	s := string(content)
+	skip := ""
+	for len(s) > 0 {
+		i := strings.IndexByte(s, '<')
+		if i < 0 || i+1 >= len(s) {
+			if skip == "" {
+				text.write(html.UnescapeString(s))
+			}
+			break
+		}
+		if next := s[i+1]; !isLetter(next) && next != '/' && next != '!' && next != '?' {
+			if skip == "" {
+				text.write(html.UnescapeString(s[:i+1]))
+			}
+			s = s[i+1:]
+			continue
+		}
+		if skip == "" {
+			text.write(html.UnescapeString(s[:i]))
+		}
+		s = s[i:]
+
+		if strings.HasPrefix(s, "<!--") {
+			end := strings.Index(s, "-->")
+			if end < 0 {
+				break
+			}
+			s = s[end+3:]
+			continue
+		}
+		end := strings.IndexByte(s, '>')
+		if end < 0 {
+			break
+		}
+		tag := s[1:end]
+		name, closing := htmlTagName(tag)
+		s = s[end+1:]
+		if skip != "" {
+			if closing && name == skip {
+				skip = ""
+			}
+			continue
+		}
+		if !closing && htmlSkipped[name] && !strings.HasSuffix(tag, "/") {
+			skip = name
+			continue
+		}
+		if htmlBlocks[name] {
+			text.newLine()
+		}
+	}
+	return text.String(), nil
+}
+
+// htmlTagName is returning the lower case name of a tag and whether it closes an element
+func htmlTagName(tag string) (string, bool) {
+	closing := strings.HasPrefix(tag, "/")
+	tag = strings.TrimPrefix(tag, "/")
+	end := 0
+	for end < len(tag) && (isLetter(tag[end]) || (end > 0 && tag[end] >= '0' && tag[end] <= '9')) {
+		end++
+	}
+	return strings.ToLower(tag[:end]), closing
+}
+
+// isLetter is checking whether c is an ascii letter
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// markdown syntax replaced by the text it marks up
+var markdownInline = []struct {
+	pattern *regexp.Regexp
+	replace string
+}{
+	{pattern: regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`), replace: "$1"},
+	{pattern: regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`), replace: "$1"},
+	{pattern: regexp.MustCompile(`\[([^\]]*)\]\[[^\]]*\]`), replace: "$1"},
+	{pattern: regexp.MustCompile(`<((?:https?|mailto):[^>\s]+)>`), replace: "$1"},
+	{pattern: regexp.MustCompile(`</?[A-Za-z][^>]*>`), replace: ""},
+	{pattern: regexp.MustCompile("`+([^`]*)`+"), replace: "$1"},
+	{pattern: regexp.MustCompile(`\*\*(\S(?:.*?\S)?)\*\*`), replace: "$1"},
+	{pattern: regexp.MustCompile(`__(\S(?:.*?\S)?)__`), replace: "$1"},
+	{pattern: regexp.MustCompile(`~~(\S(?:.*?\S)?)~~`), replace: "$1"},
+	{pattern: regexp.MustCompile(`(^|[^\w*])\*(\S(?:[^*]*\S)?)\*([^\w*]|$)`), replace: "$1$2$3"},
+	{pattern: regexp.MustCompile(`(^|[^\w_])_(\S(?:[^_]*\S)?)_([^\w_]|$)`), replace: "$1$2$3"},
+}
+
+// markdown block syntax at the beginning of lines
+var (
+	markdownFence      = regexp.MustCompile("^\\s*(```|~~~)")
+	markdownRule       = regexp.MustCompile(`^\s{0,3}([-*_])(\s*[-*_]){2,}\s*$`)
+	markdownTableRule  = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+	markdownDefinition = regexp.MustCompile(`^\s{0,3}\[[^\]]+\]:\s+\S`)
+	markdownBlock      = regexp.MustCompile(`^\s{0,3}(?:>\s?)*(?:#{1,6}\s+|[-*+]\s+(?:\[[ xX]\]\s+)?|\d+[.)]\s+)?`)
+	markdownHeading    = regexp.MustCompile(`\s+#+\s*$`)
+)
+
+// extractMarkdown is returning the text of a markdown document without the
+// markup, every line stays on its line so that line numbers are the same
+func extractMarkdown(content []byte) (string, error) {
+	lines := strings.Split(string(content), "\n")
+	frontMatter := len(lines) > 0 && strings.TrimRight(lines[0], "\r") == "---"
+	fenced := false
+	for i, line := range lines {
+		line = strings.TrimRight(line, "\r")
+		switch {
+		case frontMatter:
+			if i > 0 && (line == "---" || line == "...") {
+				frontMatter = false
+			}
+			line = ""
+		case markdownFence.MatchString(line):
+			fenced = !fenced
+			line = ""
+		case fenced:
+		case markdownRule.MatchString(line), markdownDefinition.MatchString(line),
+			strings.Contains(line, "-") && markdownTableRule.MatchString(line):
+			line = ""
+		default:
+			line = markdownBlock.ReplaceAllString(line, "")
+			line = markdownHeading.ReplaceAllString(line, "")
+			for _, inline := range markdownInline {
+				line = inline.pattern.ReplaceAllString(line, inline.replace)
+			}
+			if strings.HasPrefix(strings.TrimSpace(line), "|") {
+				line = strings.TrimSpace(strings.ReplaceAll(line, "|", " "))
+			}
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// zipMember is returning the content of a file of a zip archive
+func zipMember(content []byte, name string) ([]byte, error) {
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, fmt.Errorf("reading document archive failed with %v", err)
+	}
+	for _, f := range archive.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("opening %v of the document failed with %v", name, err)
+		}
+		defer rc.Close()
+		return ioutil.ReadAll(io.LimitReader(rc, maxExtractedSize))
+	}
+	return nil, fmt.Errorf("document has no %v", name)
+}
+
+// extractDOCX is returning the text of the paragraphs of a word document
+func extractDOCX(content []byte) (string, error) {
+	document, err := zipMember(content, "word/document.xml")
+	if err != nil {
+		return "", err
+	}
+
+	text := &textLines{}
+	inText := false
+	decoder := xml.NewDecoder(bytes.NewReader(document))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("reading word document failed with %v", err)
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				text.write(" ")
+			case "br", "cr":
+				text.newLine()
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				text.newLine()
+			}
+		case xml.CharData:
+			if inText {
+				text.write(string(t))
+			}
+		}
+	}
+	return text.String(), nil
+}
+
+// extractODT is returning the text of the paragraphs and headings of an
+// open document text
+func extractODT(content []byte) (string, error) {
+	document, err := zipMember(content, "content.xml")
+	if err != nil {
+		return "", err
+	}
+
+	text := &textLines{}
+	depth := 0
+	decoder := xml.NewDecoder(bytes.NewReader(document))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("reading open document failed with %v", err)
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "p", "h":
+				depth++
+			case "s":
+				spaces := 1
+				for _, attr := range t.Attr {
+					if attr.Name.Local == "c" {
+						if c, err := strconv.Atoi(attr.Value); err == nil && c >= 1 {
+							spaces = c
+						}
+					}
+				}
+				if spaces > maxODTSpaces {
+					spaces = maxODTSpaces
+				}
+				text.write(strings.Repeat(" ", spaces))
+			case "tab":
+				text.write(" ")
+			case "line-break":
+				text.newLine()
+			}
+		case xml.EndElement:
+			if t.Name.Local == "p" || t.Name.Local == "h" {
+				depth--
+				text.newLine()
+			}
+		case xml.CharData:
+			if depth > 0 {
+				text.write(string(t))
+			}
+		}
+	}
+	return text.String(), nil
+}
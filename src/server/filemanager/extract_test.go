@@ -0,0 +1,97 @@
+package filemanager
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+)
+
+// zipDocument is returning a zip archive with the given files
+func zipDocument(t *testing.T, files map[string]string) []byte {
+	var b bytes.Buffer
+	w := zip.NewWriter(&b)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func Test_extractHTML(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "blocks and entities",
+			content: "<html><head><title>Tea &amp; cake</title><style>p { color: red }</style></head>\n<body><h1>Menu</h1><p>Green <b>tea</b>\n  with   lemon</p><br/>x &lt; y</body></html>",
+			want:    "Tea & cake\nMenu\nGreen tea with lemon\nx < y\n",
+		},
+		{
+			name:    "scripts and comments",
+			content: "<div>one<script>var a = '<p>';</script><!-- <p>hidden</p> --> two</div><p>a < b</p>",
+			want:    "one two\na < b\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := extractHTML([]byte(tt.content)); err != nil || got != tt.want {
+				t.Errorf("extractHTML() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func Test_extractMarkdown(t *testing.T) {
+	content := "---\ntitle: notes\n---\n# Shopping *list* #\n\n- [x] **green** tea\n1. see [the shop](http://shop.example) or ![logo](logo.png)\n" +
+		"> quoted `code` and snake_case\n\n```go\nfmt.Println(\"hi\")\n```\n| item | price |\n|------|------:|\n| tea | 3 |\n---\n[shop]: http://shop.example"
+	want := "\n\n\nShopping list\n\ngreen tea\nsee the shop or logo\nquoted code and snake_case\n\n\nfmt.Println(\"hi\")\n\nitem   price\n\ntea   3\n\n"
+	if got, err := extractMarkdown([]byte(content)); err != nil || got != want {
+		t.Errorf("extractMarkdown() = %q, %v, want %q", got, err, want)
+	}
+}
+
+func Test_extractDOCX(t *testing.T) {
+	document := zipDocument(t, map[string]string{
+		"word/document.xml": `<?xml version="1.0"?><w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+			`<w:p><w:r><w:t>Quarterly</w:t></w:r><w:r><w:t xml:space="preserve"> report</w:t></w:r></w:p>` +
+			`<w:p><w:r><w:t>Sales</w:t><w:tab/><w:t>grew</w:t><w:br/><w:t>again</w:t></w:r></w:p></w:body></w:document>`,
+	})
+	want := "Quarterly report\nSales grew\nagain\n"
+	if got, err := extractDOCX(document); err != nil || got != want {
+		t.Errorf("extractDOCX() = %q, %v, want %q", got, err, want)
+	}
+	if _, err := extractDOCX(zipDocument(t, map[string]string{"other.xml": ""})); err == nil {
+		t.Errorf("extractDOCX() without document error = nil")
+	}
+}
+
+func Test_extractODT(t *testing.T) {
+	document := zipDocument(t, map[string]string{
+		"content.xml": `<?xml version="1.0"?><office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" ` +
+			`xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0"><office:body><office:text>` +
+			`<text:h>Minutes</text:h><text:p>Two<text:s text:c="2"/>items <text:span>agreed</text:span></text:p></office:text></office:body></office:document-content>`,
+	})
+	want := "Minutes\nTwo items agreed\n"
+	if got, err := extractODT(document); err != nil || got != want {
+		t.Errorf("extractODT() = %q, %v, want %q", got, err, want)
+	}
+
+	for _, count := range []string{"-1", "0", "9223372036854775807", "many"} {
+		document := zipDocument(t, map[string]string{
+			"content.xml": `<?xml version="1.0"?><office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" ` +
+				`xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0"><office:body><office:text>` +
+				`<text:p>Two<text:s text:c="` + count + `"/>items</text:p></office:text></office:body></office:document-content>`,
+		})
+		if got, err := extractODT(document); err != nil || got != "Two items\n" {
+			t.Errorf("extractODT() with %v spaces = %q, %v, want %q", count, got, err, "Two items\n")
+		}
+	}
+}
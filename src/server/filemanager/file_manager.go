@@ -6,38 +6,107 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 )
 
+// filesDir is the directory where all the files are stored
+// it and the other storage paths are variables so that tests can use
+// temporary directories
+var filesDir = "../files"
+
 // FileManager is an interface which is exposing functionalities
 type FileManager interface {
 	ListFiles(*http.Request) (interface{}, error)
 	AddFiles(*http.Request) (interface{}, error)
 	UpdateFiles(*http.Request) (interface{}, error)
 	RemoveFile(*http.Request) (interface{}, error)
+	DownloadFile(*http.Request) (interface{}, error)
 	WordCounts(*http.Request) (interface{}, error)
 	WordFrequency(*http.Request) (interface{}, error)
+	WordStats(*http.Request) (interface{}, error)
+	WordStatsTree(*http.Request) (interface{}, error)
+	WordFiles(*http.Request) (interface{}, error)
+	Search(*http.Request) (interface{}, error)
+	Vocabulary(*http.Request) (interface{}, error)
+	KWIC(*http.Request) (interface{}, error)
+	Collocations(*http.Request) (interface{}, error)
+	Compare(*http.Request) (interface{}, error)
+	Analyze(*http.Request) (interface{}, error)
+	Entities(*http.Request) (interface{}, error)
+	PIIConfig(*http.Request) (interface{}, error)
+	UpdatePIIConfig(*http.Request) (interface{}, error)
+	PIIReport(*http.Request) (interface{}, error)
+	FileInfo(*http.Request) (interface{}, error)
+	Describe(*http.Request) (interface{}, error)
+	Policies(*http.Request) (interface{}, error)
+	UpdatePolicies(*http.Request) (interface{}, error)
+	ListQuarantine(*http.Request) (interface{}, error)
+	Grep(http.ResponseWriter, *http.Request) error
+	Query(http.ResponseWriter, *http.Request) error
+	AppendFile(*http.Request) (interface{}, error)
+	Follow(http.ResponseWriter, *http.Request) error
+	Lines(*http.Request) (interface{}, error)
+	Head(*http.Request) (interface{}, error)
+	Tail(*http.Request) (interface{}, error)
+	Diff(*http.Request) (interface{}, error)
+	NGrams(*http.Request) (interface{}, error)
+	Keywords(*http.Request) (interface{}, error)
+	Similar(*http.Request) (interface{}, error)
+	ListStopWords(*http.Request) (interface{}, error)
+	UpdateStopWords(*http.Request) (interface{}, error)
+	RemoveStopWords(*http.Request) (interface{}, error)
 }
 
 // file is representing file details
+// ToUTF8 and LineEndings (lf or crlf) are converting an uploaded file before it is stored
 type file struct {
-	Name    string `json:"name"`
-	Content []byte `json:"content"`
+	Name        string `json:"name"`
+	Content     []byte `json:"content"`
+	ToUTF8      bool   `json:"to_utf8,omitempty"`
+	LineEndings string `json:"line_endings,omitempty"`
+}
+
+// uploadResponse is representing what was flagged while adding or updating
+// files, quarantined files are not stored with the other files
+type uploadResponse struct {
+	PII         []piiFileReport    `json:"pii,omitempty"`
+	Secrets     []secretFileReport `json:"secrets,omitempty"`
+	Quarantined []string           `json:"quarantined,omitempty"`
+}
+
+// downloadRequest is representing the download file request
+// Redact is replacing pii even when the pii action is not redact
+type downloadRequest struct {
+	Name   string `json:"name"`
+	Redact bool   `json:"redact"`
 }
 
 // wordFrequencyRequest is representing the frequent words request
+// Offset or Cursor (from a previous response) selects the page
 type wordFrequencyRequest struct {
-	Limit int    `json:"limit"`
-	Order string `json:"order"`
+	analysisOptions
+	Limit  int    `json:"limit"`
+	Order  string `json:"order"`
+	Offset int    `json:"offset"`
+	Cursor string `json:"cursor"`
 }
 
 // wordFrequencyResponse is representing frequent words response
 type wordFrequencyResponse struct {
-	Words []string `json:"words"`
+	Words      []wordFrequency `json:"words"`
+	Total      int             `json:"total"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+// wordCountRequest is representing the word count request
+type wordCountRequest struct {
+	analysisOptions
 }
 
 // wordCountResponse is representing word count response
@@ -46,7 +115,13 @@ type wordCountResponse struct {
 }
 
 // file manager is implementing FileManager
-type fileManager struct{}
+// index is the full text search index, it is built on the first search
+// appending is serialising appends and watchers are the followers of files
+type fileManager struct {
+	index     invertedIndex
+	appending sync.Mutex
+	watchers  fileWatchers
+}
 
 func NewFileManager() FileManager {
 	return &fileManager{}
@@ -54,7 +129,7 @@ func NewFileManager() FileManager {
 
 // ListFiles is returning list of all the files stored
 func (fm *fileManager) ListFiles(_ *http.Request) (interface{}, error) {
-	return readDir("../files")
+	return readDir(filesDir)
 }
 
 // readDir is returning all the files inside in a file path
@@ -89,13 +164,43 @@ func (fm *fileManager) AddFiles(r *http.Request) (interface{}, error) {
 			return nil, err
 		}
 	}
+	converted, err := normalizeUpload(files)
+	if err != nil {
+		return nil, err
+	}
+	metas, err := checkContentTypes(files)
+	if err != nil {
+		return nil, err
+	}
+	for name, encoding := range converted {
+		metas[name].OriginalEncoding = encoding
+	}
+	pii, secrets, err := scanUpload(files)
+	if err != nil {
+		return nil, err
+	}
 
+	response := &uploadResponse{PII: pii, Secrets: secrets}
 	for _, file := range files {
+		if quarantined(secrets, file.Name) {
+			if err := quarantineFile(file); err != nil {
+				return nil, err
+			}
+			response.Quarantined = append(response.Quarantined, file.Name)
+			continue
+		}
 		if err := createFile(file); err != nil {
 			return nil, err
 		}
+		if err := writeMeta(metas[file.Name]); err != nil {
+			return nil, err
+		}
+		if err := writeLineIndex(metas[file.Name], false); err != nil {
+			return nil, err
+		}
+		fm.index.update(file.Name)
 	}
-	return nil, nil
+	return response.orNil(), nil
 }
 
 // UpdateFiles is updating/creating files coming in the request
@@ -107,11 +212,69 @@ func (fm *fileManager) UpdateFiles(r *http.Request) (interface{}, error) {
 	}
 
 	for _, file := range files {
+		if _, err := getFilePath(file.Name); err != nil {
+			return nil, err
+		}
+	}
+	converted, err := normalizeUpload(files)
+	if err != nil {
+		return nil, err
+	}
+	metas, err := checkContentTypes(files)
+	if err != nil {
+		return nil, err
+	}
+	for name, encoding := range converted {
+		metas[name].OriginalEncoding = encoding
+	}
+	pii, secrets, err := scanUpload(files)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &uploadResponse{PII: pii, Secrets: secrets}
+	for _, file := range files {
+		if quarantined(secrets, file.Name) {
+			if err := quarantineFile(file); err != nil {
+				return nil, err
+			}
+			response.Quarantined = append(response.Quarantined, file.Name)
+			continue
+		}
 		if err := updateFile(file); err != nil {
 			return nil, err
 		}
+		if err := writeMeta(metas[file.Name]); err != nil {
+			return nil, err
+		}
+		if err := writeLineIndex(metas[file.Name], false); err != nil {
+			return nil, err
+		}
+		fm.index.update(file.Name)
 	}
-	return nil, nil
+	return response.orNil(), nil
+}
+
+// scanUpload is scanning uploaded files for pii and secrets, both scans run
+// before either rejects so that every secret is logged
+func scanUpload(files []file) ([]piiFileReport, []secretFileReport, error) {
+	pii, piiErr := checkPII(files)
+	secrets, err := checkSecrets(files)
+	if piiErr != nil {
+		return nil, nil, piiErr
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return pii, secrets, nil
+}
+
+// orNil is returning nothing when nothing was flagged
+func (ur *uploadResponse) orNil() interface{} {
+	if len(ur.PII) == 0 && len(ur.Secrets) == 0 {
+		return nil
+	}
+	return ur
 }
 
 // RemoveFile is deleting the file coming in the request
@@ -126,13 +289,98 @@ func (fm *fileManager) RemoveFile(r *http.Request) (interface{}, error) {
 	if err := removeFile(fileDetail); err != nil {
 		return nil, err
 	}
+	if err := removeMeta(fileDetail.Name); err != nil {
+		return nil, err
+	}
+	if err := removeLineIndex(fileDetail.Name); err != nil {
+		return nil, err
+	}
+	fm.index.remove(fileDetail.Name)
 	return nil, nil
 }
 
+// DownloadFile is returning the content of a stored file
+// pii is redacted when the pii action is redact or the request asks for it,
+// text is redacted in its own encoding, documents are refused as they can
+// not be redacted and other files are served unchanged
+func (fm *fileManager) DownloadFile(r *http.Request) (interface{}, error) {
+	downloadRequest := &downloadRequest{}
+	if err := decodeBody(r, downloadRequest); err != nil {
+		return nil, fmt.Errorf("download file request body decoding failed with %v", err)
+	}
+
+	filePath, err := getFilePath(downloadRequest.Name)
+	if err != nil {
+		return nil, err
+	}
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, &statusError{code: http.StatusNotFound, err: fmt.Errorf("file does not exist %v", downloadRequest.Name)}
+		}
+		return nil, fmt.Errorf("read file failed with error %v", err)
+	}
+
+	config, err := loadPIIConfig()
+	if err != nil {
+		return nil, err
+	}
+	if downloadRequest.Redact || config.Action == piiActionRedact {
+		meta, err := loadMeta(filePath)
+		if err != nil {
+			return nil, err
+		}
+		scanner, err := newPIIScanner(config)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := extractorFor(meta.ContentType); ok && !meta.Text {
+			return nil, &statusError{
+				code: http.StatusUnprocessableEntity,
+				err:  fmt.Errorf("document %v can not be downloaded redacted", downloadRequest.Name),
+			}
+		}
+		if meta.Text {
+			text, err := decodeText(content, meta.Encoding)
+			if err != nil {
+				return nil, fmt.Errorf("decoding file %v failed with %v", downloadRequest.Name, err)
+			}
+			content = encodeText(string(scanner.redact([]byte(text))), meta.Encoding, meta.BOM)
+		}
+	}
+	return &file{
+		Name:    downloadRequest.Name,
+		Content: content,
+	}, nil
+}
+
+// decodeBody is decoding the optional json request body into v
+// an empty body leaves v untouched
+func decodeBody(r *http.Request, v interface{}) error {
+	if r.Body == nil {
+		return nil
+	}
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(v); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
+
 // WordCounts is counting all the words from all files stored and
 // returning the count (unique word count)
-func (fm *fileManager) WordCounts(*http.Request) (interface{}, error) {
-	wordCounts, err := getWordCounts()
+func (fm *fileManager) WordCounts(r *http.Request) (interface{}, error) {
+	wordCountRequest := &wordCountRequest{}
+	if err := decodeBody(r, wordCountRequest); err != nil {
+		return nil, fmt.Errorf("word count request body decoding failed with %v", err)
+	}
+
+	analyzer, err := newAnalyzer(wordCountRequest.analysisOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	wordCounts, err := getWordCounts(analyzer)
 	if err != nil {
 		return nil, err
 	}
@@ -144,6 +392,7 @@ func (fm *fileManager) WordCounts(*http.Request) (interface{}, error) {
 // WordFrequency is counting all the words and
 // returning number of words mentioned in the request as limit
 // in ascending or descending format as mentioned by order in the request
+// words are ranked by count, ties are broken alphabetically
 func (fm *fileManager) WordFrequency(r *http.Request) (interface{}, error) {
 	wordFrequency := &wordFrequencyRequest{}
 	decoder := json.NewDecoder(r.Body)
@@ -151,110 +400,160 @@ func (fm *fileManager) WordFrequency(r *http.Request) (interface{}, error) {
 		return nil, fmt.Errorf("word frequency request body decoding failed with %v", err)
 	}
 
-	wordCounts, err := getWordCounts()
+	offset, err := pageOffset(wordFrequency.Offset, wordFrequency.Cursor)
 	if err != nil {
 		return nil, err
 	}
 
-	words := make([]string, 0, len(wordCounts))
-	for word := range wordCounts {
-		words = append(words, word)
+	analyzer, err := newAnalyzer(wordFrequency.analysisOptions)
+	if err != nil {
+		return nil, err
 	}
 
-	sort.SliceStable(words, func(i, j int) bool {
-		return wordCounts[words[i]] < wordCounts[words[j]]
-	})
-
-	wordFrequencyResponse := &wordFrequencyResponse{}
-
-	if wordFrequency.Limit > len(words) {
-		wordFrequency.Limit = len(words)
+	wordCounts, err := getWordCounts(analyzer)
+	if err != nil {
+		return nil, err
 	}
 
-	wordFrequencyResponse.Words = words[len(words)-wordFrequency.Limit:]
-	switch wordFrequency.Order {
-	case "asc":
-		// no-op
-	case "dsc":
-		for i := 0; i < (len(wordFrequencyResponse.Words) / 2); i++ {
-			wordFrequencyResponse.Words[i], wordFrequencyResponse.Words[len(wordFrequencyResponse.Words)-i-1] = wordFrequencyResponse.Words[len(wordFrequencyResponse.Words)-i-1], wordFrequencyResponse.Words[i]
-		}
-	default:
-		return nil, fmt.Errorf("invalid order type %v %v", wordFrequency.Order, wordFrequency.Limit)
+	ranked := rankWords(wordCounts)
+	page, next, err := paginate(ranked, offset, wordFrequency.Limit, wordFrequency.Order)
+	if err != nil {
+		return nil, err
 	}
 
-	return wordFrequencyResponse, nil
+	return &wordFrequencyResponse{
+		Words:      page,
+		Total:      len(ranked),
+		NextCursor: next,
+	}, nil
 }
 
 // getWordCounts is counting words from all the files
+func getWordCounts(a *analyzer) (map[string]int, error) {
+	analyses, err := analyzeFiles(a)
+	if err != nil {
+		return nil, err
+	}
+	return mergeCounts(analyses), nil
+}
+
+// analyzeFiles is analysing all the files selected by the analyzer options
 // reading them in go routines (wordCounts)
-func getWordCounts() (map[string]int, error) {
-	files, err := readDir("../files")
+// analyses are sorted by file name
+func analyzeFiles(a *analyzer) ([]*fileAnalysis, error) {
+	files, err := readDir(filesDir)
 	if err != nil {
 		return nil, fmt.Errorf("error while reading files for word count %v", err)
 	}
 
-	c := make(chan map[string]int, len(files))
-	errChan := make(chan error)
+	files, err = a.options.selectPaths(files)
+	if err != nil {
+		return nil, err
+	}
+	if !a.options.IncludeBinary {
+		if files, err = textPaths(files); err != nil {
+			return nil, err
+		}
+	}
+
+	c := make(chan *fileAnalysis, len(files))
+	errChan := make(chan error, len(files))
 	for _, file := range files {
-		go wordCounts(c, errChan, file)
+		go wordCounts(c, errChan, file, a)
 	}
 
-	wordCounts := make(map[string]int)
+	analyses := make([]*fileAnalysis, 0, len(files))
 	for i := 0; i < len(files); i++ {
 		select {
-		case counts := <-c:
-			for word, count := range counts {
-				wordCounts[word] += count
-			}
+		case analysis := <-c:
+			analyses = append(analyses, analysis)
 		case err := <-errChan:
 			return nil, err
 		}
 	}
-	return wordCounts, nil
+
+	sort.Slice(analyses, func(i, j int) bool {
+		return analyses[i].Name < analyses[j].Name
+	})
+	return analyses, nil
 }
 
-// wordCounts is reading words from a file (fileName)
+// wordCounts is reading words from a file (fileName) using the analyzer (a)
 // if err occured writing error to errChan
-// otherwise writing words count map to c chan
-func wordCounts(c chan<- map[string]int, errChan chan<- error, fileName string) {
-	wordCounts := make(map[string]int)
-	file, err := os.Open(fileName)
+// otherwise writing the file analysis to c chan
+func wordCounts(c chan<- *fileAnalysis, errChan chan<- error, fileName string, a *analyzer) {
+	analysis, err := analyzeFile(fileName, a)
+	if err != nil {
+		errChan <- err
+		return
+	}
+	c <- analysis
+}
+
+// analyzeFile is reading words from a file (fileName) using the analyzer (a)
+func analyzeFile(fileName string, a *analyzer) (*fileAnalysis, error) {
+	file, err := openText(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("error while opening file %v with error %v", fileName, err)
+	}
 	defer file.Close()
+
+	analysis, err := analyzeReader(relativeName(fileName), file, a)
 	if err != nil {
-		errChan <- fmt.Errorf("error while opening file %v with error %v", fileName, err)
+		return nil, fmt.Errorf("error while reading from file %v with error %v", fileName, err)
 	}
+	return analysis, nil
+}
 
-	rdr := bufio.NewReader(file)
+// analyzeReader is reading words line by line from rdr using the analyzer (a)
+func analyzeReader(name string, r io.Reader, a *analyzer) (*fileAnalysis, error) {
+	analysis := newFileAnalysis(name)
+	rdr := bufio.NewReader(r)
 	for {
 		line, err := rdr.ReadString('\n')
-		if line == "" {
-			break
-		} else {
-			words := strings.Fields(line)
-			for _, word := range words {
-				word = strings.ToLower(word)
-				wordCounts[word]++
-			}
+		if line != "" {
+			analysis.addLine(line, a)
 		}
 		if err != nil {
 			if err != io.EOF {
-				errChan <- fmt.Errorf("error while reading from file %v with error %v", fileName, err)
-				break
+				return nil, err
 			}
 			break
 		}
 	}
-	c <- wordCounts
+	return analysis, nil
+}
+
+// relativeName is returning the name of a file relative to the files directory
+// fPath can be relative (as returned by readDir) or absolute (getFilePath)
+func relativeName(fPath string) string {
+	base, err := filepath.Abs(filesDir)
+	if err != nil {
+		return fPath
+	}
+	absPath, err := filepath.Abs(fPath)
+	if err != nil {
+		return fPath
+	}
+	name, err := filepath.Rel(base, absPath)
+	if err != nil {
+		return fPath
+	}
+	return filepath.ToSlash(name)
 }
 
 // getFilePath is returning the Absolute file path of a file
 // file path is used to store and retrieve the file
+// names which are absolute or lead outside of the files directory are rejected
 func getFilePath(fileName string) (string, error) {
-	filePath, err := filepath.Abs("../files/" + fileName)
+	base, err := filepath.Abs(filesDir)
 	if err != nil {
 		return "", fmt.Errorf("error while creating file path %v", err)
 	}
+	filePath := filepath.Join(base, fileName)
+	if filepath.IsAbs(fileName) || !strings.HasPrefix(filePath, base+string(filepath.Separator)) {
+		return "", &statusError{code: http.StatusBadRequest, err: fmt.Errorf("invalid file name %v, it is not inside the files directory", fileName)}
+	}
 	return filePath, nil
 }
 
@@ -279,10 +578,13 @@ func checkFileStatus(fileDetail file) error {
 func createFile(fileDetail file) error {
 	filePath, err := getFilePath(fileDetail.Name)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	f, err := os.OpenFile(filePath, os.O_CREATE, 0666)
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("create directory failed with error %v", err)
+	}
+	f, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0666)
 	if err != nil {
 		return fmt.Errorf("create file failed with error %v", err)
 	}
@@ -299,9 +601,12 @@ func createFile(fileDetail file) error {
 func updateFile(fileDetail file) error {
 	filePath, err := getFilePath(fileDetail.Name)
 	if err != nil {
-		return nil
+		return err
 	}
 
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("create directory failed with error %v", err)
+	}
 	f, err := os.Create(filePath)
 	if err != nil {
 		return fmt.Errorf("open file failed with error %v", err)
@@ -319,7 +624,7 @@ func updateFile(fileDetail file) error {
 func removeFile(fileDetail file) error {
 	filePath, err := getFilePath(fileDetail.Name)
 	if err != nil {
-		return nil
+		return err
 	}
 
 	err = os.Remove(filePath)
@@ -0,0 +1,84 @@
+package filemanager
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// fileFilter is selecting the files a request is working on
+// Files are file names, Globs are patterns matched against the file name
+// (patterns without a slash are matched against the base name as well)
+// and Prefix is a name prefix like a directory ("subfiles/")
+// without files and globs all the files with the prefix are selected
+type fileFilter struct {
+	Files  []string `json:"files"`
+	Globs  []string `json:"globs"`
+	Prefix string   `json:"prefix"`
+}
+
+// validate is checking that all the glob patterns are valid
+func (ff fileFilter) validate() error {
+	for _, glob := range ff.Globs {
+		if _, err := path.Match(glob, ""); err != nil {
+			return fmt.Errorf("invalid glob pattern %v", glob)
+		}
+	}
+	return nil
+}
+
+// empty is checking whether the filter has no criteria
+func (ff fileFilter) empty() bool {
+	return len(ff.Files) == 0 && len(ff.Globs) == 0 && ff.Prefix == ""
+}
+
+// matches is checking whether a file (name relative to the files directory) is selected
+func (ff fileFilter) matches(name string) bool {
+	if !strings.HasPrefix(name, ff.Prefix) {
+		return false
+	}
+	if len(ff.Files) == 0 && len(ff.Globs) == 0 {
+		return true
+	}
+	for _, file := range ff.Files {
+		if file == name {
+			return true
+		}
+	}
+	for _, glob := range ff.Globs {
+		if ok, _ := path.Match(glob, name); ok {
+			return true
+		}
+		if !strings.Contains(glob, "/") {
+			if ok, _ := path.Match(glob, path.Base(name)); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// selectPaths is returning the paths of the files selected by the filter
+// if a named file is not stored then it is an error
+func (ff fileFilter) selectPaths(paths []string) ([]string, error) {
+	if err := ff.validate(); err != nil {
+		return nil, err
+	}
+
+	stored := make(map[string]bool, len(paths))
+	selected := []string{}
+	for _, fPath := range paths {
+		name := relativeName(fPath)
+		stored[name] = true
+		if ff.matches(name) {
+			selected = append(selected, fPath)
+		}
+	}
+
+	for _, file := range ff.Files {
+		if !stored[file] {
+			return nil, fmt.Errorf("file does not exist %v", file)
+		}
+	}
+	return selected, nil
+}
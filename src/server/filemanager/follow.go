@@ -0,0 +1,422 @@
+package filemanager
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultFollowLines is the number of last lines sent before the appended ones
+const defaultFollowLines = 10
+
+// followInterval is how often a followed file is checked for lines which
+// were not appended through the store, appends through the store wake the
+// followers at once
+const followInterval = 250 * time.Millisecond
+
+// heartbeatInterval is how often an idle event stream is sent a comment so
+// that proxies do not close it
+const heartbeatInterval = 15 * time.Second
+
+// follow record types, removed is the last record of a stream
+const (
+	followLine      = "line"
+	followTruncated = "truncated"
+	followRemoved   = "removed"
+)
+
+// appendRequest is representing the append request, a file which does not
+// exist is created
+type appendRequest struct {
+	Name    string `json:"name"`
+	Content []byte `json:"content"`
+}
+
+// appendResponse is representing where the content was written and what was
+// flagged in it, Offset is the size of the file before the append
+type appendResponse struct {
+	Name    string             `json:"name"`
+	Offset  int64              `json:"offset"`
+	Size    int64              `json:"size"`
+	PII     []piiFileReport    `json:"pii,omitempty"`
+	Secrets []secretFileReport `json:"secrets,omitempty"`
+}
+
+// followRequest is representing the follow request
+// Lines is the number of last lines sent first (10 when not given), From is
+// the offset to resume from instead
+type followRequest struct {
+	Name  string `json:"name"`
+	Lines *int   `json:"lines"`
+	From  *int64 `json:"from"`
+}
+
+// followRecord is representing a streamed line of a followed file
+// Offset is where the next line starts, following again from it resumes the stream
+type followRecord struct {
+	Type   string `json:"type"`
+	Offset int64  `json:"offset"`
+	Text   string `json:"text,omitempty"`
+}
+
+// fileWatchers is waking the followers of a file when lines are appended to it
+// the zero value has no followers
+type fileWatchers struct {
+	mu       sync.Mutex
+	watchers map[string]map[chan struct{}]bool
+}
+
+// watch is returning a channel which is signalled on appends to a file and
+// a function to stop watching
+func (fw *fileWatchers) watch(name string) (<-chan struct{}, func()) {
+	c := make(chan struct{}, 1)
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+	if fw.watchers == nil {
+		fw.watchers = make(map[string]map[chan struct{}]bool)
+	}
+	if fw.watchers[name] == nil {
+		fw.watchers[name] = make(map[chan struct{}]bool)
+	}
+	fw.watchers[name][c] = true
+	return c, func() {
+		fw.mu.Lock()
+		defer fw.mu.Unlock()
+		delete(fw.watchers[name], c)
+		if len(fw.watchers[name]) == 0 {
+			delete(fw.watchers, name)
+		}
+	}
+}
+
+// notify is waking the followers of a file, a follower which was not woken
+// yet since the last append is not woken twice
+func (fw *fileWatchers) notify(name string) {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+	for c := range fw.watchers[name] {
+		select {
+		case c <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// AppendFile is writing content at the end of a file in a single write so
+// that concurrent appends are not interleaved, the content is scanned as an
+// upload and written in the encoding of the file
+// the search index is updated with the appended lines a moment later
+func (fm *fileManager) AppendFile(r *http.Request) (interface{}, error) {
+	appendRequest := &appendRequest{}
+	if err := decodeBody(r, appendRequest); err != nil {
+		return nil, fmt.Errorf("append request body decoding failed with %v", err)
+	}
+	if appendRequest.Name == "" {
+		return nil, &statusError{code: http.StatusBadRequest, err: errors.New("name of the file to append to is missing")}
+	}
+	filePath, err := getFilePath(appendRequest.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	fm.appending.Lock()
+	defer fm.appending.Unlock()
+
+	upload := []file{{Name: appendRequest.Name, Content: appendRequest.Content}}
+	content := appendRequest.Content
+	meta, err := loadMeta(filePath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		if _, err := checkContentTypes(upload); err != nil {
+			return nil, err
+		}
+		meta = nil
+	case err != nil:
+		return nil, err
+	case !meta.Text:
+		return nil, &statusError{code: http.StatusUnsupportedMediaType, err: fmt.Errorf("can not append to file %v of type %v", appendRequest.Name, mediaTypeOf(meta.ContentType))}
+	case meta.Encoding != "" && meta.Encoding != encodingUTF8:
+		content = encodeText(string(content), meta.Encoding, false)
+	}
+	pii, secrets, err := scanUpload(upload)
+	if err != nil {
+		return nil, err
+	}
+	if quarantined(secrets, appendRequest.Name) {
+		return nil, &statusError{
+			code: http.StatusUnprocessableEntity,
+			err:  fmt.Errorf("append rejected, secrets found in %v and appended lines can not be quarantined", appendRequest.Name),
+		}
+	}
+
+	offset, err := appendToFile(filePath, content)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := sniffFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if meta != nil {
+		updated.OriginalEncoding = meta.OriginalEncoding
+	}
+	if err := writeMeta(updated); err != nil {
+		return nil, err
+	}
+	if err := writeLineIndex(updated, true); err != nil {
+		return nil, err
+	}
+	fm.index.updateAppended(appendRequest.Name)
+	fm.watchers.notify(appendRequest.Name)
+	return &appendResponse{Name: appendRequest.Name, Offset: offset, Size: updated.Size, PII: pii, Secrets: secrets}, nil
+}
+
+// appendToFile is writing content at the end of a file, it is returning the
+// offset it was written at
+func appendToFile(filePath string, content []byte) (int64, error) {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return 0, fmt.Errorf("create directory failed with error %v", err)
+	}
+	f, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	if err != nil {
+		return 0, fmt.Errorf("open file failed with error %v", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	if _, err := f.Write(content); err != nil {
+		return 0, fmt.Errorf("append to file failed with error %v", err)
+	}
+	return info.Size(), nil
+}
+
+// Follow is streaming the last lines of a file and then every line appended
+// to it until the client goes away, as server sent events when the client
+// accepts text/event-stream and as ndjson otherwise
+// a line is sent once it is complete, a truncated file is followed from its
+// beginning and the stream ends when the file is removed
+// utf-16 files are not followed, the follower splits lines on single bytes
+func (fm *fileManager) Follow(w http.ResponseWriter, r *http.Request) error {
+	followRequest := &followRequest{}
+	if err := decodeBody(r, followRequest); err != nil {
+		return fmt.Errorf("follow request body decoding failed with %v", err)
+	}
+	filePath, err := getFilePath(followRequest.Name)
+	if err != nil {
+		return err
+	}
+	meta, err := loadMeta(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return &statusError{code: http.StatusNotFound, err: fmt.Errorf("file does not exist %v", followRequest.Name)}
+	}
+	if err != nil {
+		return err
+	}
+	if !meta.Text || meta.Encoding == encodingUTF16LE || meta.Encoding == encodingUTF16BE {
+		return &statusError{code: http.StatusUnsupportedMediaType, err: fmt.Errorf("file %v of type %v can not be followed line by line", followRequest.Name, meta.ContentType)}
+	}
+
+	offset, err := followOffset(r, followRequest, filePath)
+	if err != nil {
+		return err
+	}
+	scanner, err := redactingScanner()
+	if err != nil {
+		return err
+	}
+	sse := strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+	f := &follower{encoding: meta.Encoding, scanner: scanner, offset: offset, flush: func() {}}
+	if flusher, ok := w.(http.Flusher); ok {
+		f.flush = flusher.Flush
+	}
+	if sse {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		f.send = func(record *followRecord) error { return writeEvent(w, record) }
+	} else {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		encoder := json.NewEncoder(w)
+		f.send = func(record *followRecord) error { return encoder.Encode(record) }
+	}
+
+	appended, stop := fm.watchers.watch(followRequest.Name)
+	defer stop()
+	ticker := time.NewTicker(followInterval)
+	defer ticker.Stop()
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		removed, err := f.read(filePath)
+		if err != nil {
+			return err
+		}
+		if removed {
+			return nil
+		}
+		select {
+		case <-r.Context().Done():
+			return nil
+		case <-appended:
+		case <-ticker.C:
+		case <-heartbeat.C:
+			if sse {
+				if _, err := io.WriteString(w, ": heartbeat\n\n"); err != nil {
+					return err
+				}
+				f.flush()
+			}
+		}
+	}
+}
+
+// followOffset is returning where a follow stream starts, the id of the
+// last received event resumes a stream of server sent events
+func followOffset(r *http.Request, request *followRequest, filePath string) (int64, error) {
+	if id := r.Header.Get("Last-Event-ID"); id != "" {
+		offset, err := strconv.ParseInt(id, 10, 64)
+		if err != nil || offset < 0 {
+			return 0, &statusError{code: http.StatusBadRequest, err: fmt.Errorf("invalid last event id %v", id)}
+		}
+		return offset, nil
+	}
+	if request.From != nil {
+		if *request.From < 0 {
+			return 0, &statusError{code: http.StatusBadRequest, err: fmt.Errorf("invalid offset %v", *request.From)}
+		}
+		return *request.From, nil
+	}
+	lines := defaultFollowLines
+	if request.Lines != nil {
+		lines = *request.Lines
+	}
+	file, err := os.Open(filePath)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+	return tailOffset(file, info.Size(), lines)
+}
+
+// tailOffset is returning the offset of the last n lines of a file, the
+// file is read backwards from its end
+func tailOffset(file io.ReaderAt, size int64, n int) (int64, error) {
+	if n <= 0 {
+		return size, nil
+	}
+	buf := make([]byte, 4096)
+	newlines := 0
+	for end := size; end > 0; {
+		start := end - int64(len(buf))
+		if start < 0 {
+			start = 0
+		}
+		chunk := buf[:end-start]
+		if _, err := file.ReadAt(chunk, start); err != nil && err != io.EOF {
+			return 0, err
+		}
+		for i := len(chunk) - 1; i >= 0; i-- {
+			// the line ending of the last line does not start a line
+			if chunk[i] != '\n' || start+int64(i) == size-1 {
+				continue
+			}
+			newlines++
+			if newlines == n {
+				return start + int64(i) + 1, nil
+			}
+		}
+		end = start
+	}
+	return 0, nil
+}
+
+// follower is sending the lines of a followed file from offset, the
+// beginning of the line which is not sent yet, pii is redacted with scanner
+// when it is set
+type follower struct {
+	encoding string
+	scanner  *piiScanner
+	offset   int64
+	send     func(*followRecord) error
+	flush    func()
+}
+
+// read is sending the complete lines after the offset, it is returning
+// whether the file was removed
+func (f *follower) read(filePath string) (bool, error) {
+	file, err := os.Open(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		err := f.send(&followRecord{Type: followRemoved, Offset: f.offset})
+		f.flush()
+		return true, err
+	}
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return false, err
+	}
+	if info.Size() < f.offset {
+		f.offset = 0
+		if err := f.send(&followRecord{Type: followTruncated}); err != nil {
+			return false, err
+		}
+	}
+	if info.Size() == f.offset {
+		f.flush()
+		return false, nil
+	}
+
+	if _, err := file.Seek(f.offset, io.SeekStart); err != nil {
+		return false, err
+	}
+	rdr := bufio.NewReader(io.LimitReader(file, info.Size()-f.offset))
+	for {
+		line, err := rdr.ReadBytes('\n')
+		if err == io.EOF {
+			// the last line is sent once it is complete
+			break
+		}
+		if err != nil {
+			return false, fmt.Errorf("error while reading file %v with error %v", relativeName(filePath), err)
+		}
+		start := f.offset
+		f.offset += int64(len(line))
+		if err := f.send(&followRecord{Type: followLine, Offset: f.offset, Text: f.scanner.redactText(lineText(line, f.encoding, start == 0))}); err != nil {
+			return false, err
+		}
+	}
+	f.flush()
+	return false, nil
+}
+
+// writeEvent is writing a record as a server sent event, the offset is the
+// id of the event and lines are sent as the default message event
+// a carriage return inside a line would end the data of the event
+func writeEvent(w io.Writer, record *followRecord) error {
+	var err error
+	switch record.Type {
+	case followLine:
+		_, err = fmt.Fprintf(w, "id: %d\ndata: %s\n\n", record.Offset, strings.ReplaceAll(record.Text, "\r", ""))
+	default:
+		_, err = fmt.Fprintf(w, "event: %s\nid: %d\ndata: \n\n", record.Type, record.Offset)
+	}
+	return err
+}
@@ -0,0 +1,51 @@
+package filemanager
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func Test_tailOffset(t *testing.T) {
+	content := "one\ntwo\nthree\n" + strings.Repeat("x", 5000) + "\nlast"
+	tests := []struct {
+		name    string
+		content string
+		lines   int
+		want    int64
+	}{
+		{name: "last line without line ending", content: content, lines: 1, want: int64(len(content) - 4)},
+		{name: "across chunks", content: content, lines: 3, want: 8},
+		{name: "more lines than the file", content: content, lines: 10, want: 0},
+		{name: "last line with line ending", content: "a\nb\n", lines: 1, want: 2},
+		{name: "no lines", content: "a\nb\n", lines: 0, want: 4},
+		{name: "empty file", content: "", lines: 5, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tailOffset(strings.NewReader(tt.content), int64(len(tt.content)), tt.lines)
+			if err != nil || got != tt.want {
+				t.Errorf("tailOffset() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func Test_writeEvent(t *testing.T) {
+	tests := []struct {
+		name   string
+		record followRecord
+		want   string
+	}{
+		{name: "line", record: followRecord{Type: followLine, Offset: 12, Text: "GET /\r done"}, want: "id: 12\ndata: GET / done\n\n"},
+		{name: "truncated", record: followRecord{Type: followTruncated}, want: "event: truncated\nid: 0\ndata: \n\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := writeEvent(&b, &tt.record); err != nil || b.String() != tt.want {
+				t.Errorf("writeEvent() = %q, %v, want %q", b.String(), err, tt.want)
+			}
+		})
+	}
+}
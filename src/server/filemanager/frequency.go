@@ -0,0 +1,112 @@
+package filemanager
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// cursorPrefix is prepended to the offset before encoding it as a cursor
+const cursorPrefix = "offset:"
+
+// wordFrequency is representing a word with its count and rank
+// words with the same count share the same rank
+type wordFrequency struct {
+	Word  string `json:"word"`
+	Count int    `json:"count"`
+	Rank  int    `json:"rank"`
+}
+
+// rankWords is sorting words by count (highest first)
+// ties are broken by the word itself so the result is deterministic
+func rankWords(wordCounts map[string]int) []wordFrequency {
+	ranked := make([]wordFrequency, 0, len(wordCounts))
+	for word, count := range wordCounts {
+		ranked = append(ranked, wordFrequency{Word: word, Count: count})
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Count != ranked[j].Count {
+			return ranked[i].Count > ranked[j].Count
+		}
+		return ranked[i].Word < ranked[j].Word
+	})
+
+	for i := range ranked {
+		if i > 0 && ranked[i].Count == ranked[i-1].Count {
+			ranked[i].Rank = ranked[i-1].Rank
+			continue
+		}
+		ranked[i].Rank = i + 1
+	}
+	return ranked
+}
+
+// paginate is returning limit entries of ranked starting at offset
+// "dsc" keeps the ranked order, "asc" reverses the page
+// next cursor is empty when there are no more entries
+func paginate(ranked []wordFrequency, offset, limit int, order string) ([]wordFrequency, string, error) {
+	if order != "asc" && order != "dsc" {
+		return nil, "", &statusError{code: http.StatusBadRequest, err: fmt.Errorf("invalid order type %v %v", order, limit)}
+	}
+	if offset < 0 || limit < 0 {
+		return nil, "", &statusError{code: http.StatusBadRequest, err: fmt.Errorf("invalid page offset %v limit %v", offset, limit)}
+	}
+
+	if offset > len(ranked) {
+		offset = len(ranked)
+	}
+	end := offset + limit
+	if end > len(ranked) {
+		end = len(ranked)
+	}
+
+	page := make([]wordFrequency, end-offset)
+	copy(page, ranked[offset:end])
+	if order == "asc" {
+		for i := 0; i < len(page)/2; i++ {
+			page[i], page[len(page)-i-1] = page[len(page)-i-1], page[i]
+		}
+	}
+
+	next := ""
+	if end < len(ranked) {
+		next = encodeCursor(end)
+	}
+	return page, next, nil
+}
+
+// pageOffset is resolving the page offset from a cursor if present
+// otherwise the offset is used as it is
+func pageOffset(offset int, cursor string) (int, error) {
+	if cursor == "" {
+		return offset, nil
+	}
+	return decodeCursor(cursor)
+}
+
+// encodeCursor is encoding the offset of the next page
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(offset)))
+}
+
+// decodeCursor is decoding a cursor returned by encodeCursor
+func decodeCursor(cursor string) (int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, &statusError{code: http.StatusBadRequest, err: fmt.Errorf("invalid cursor %v", cursor)}
+	}
+
+	value := string(data)
+	if len(value) <= len(cursorPrefix) || value[:len(cursorPrefix)] != cursorPrefix {
+		return 0, &statusError{code: http.StatusBadRequest, err: fmt.Errorf("invalid cursor %v", cursor)}
+	}
+
+	offset, err := strconv.Atoi(value[len(cursorPrefix):])
+	if err != nil || offset < 0 {
+		return 0, &statusError{code: http.StatusBadRequest, err: fmt.Errorf("invalid cursor %v", cursor)}
+	}
+	return offset, nil
+}
@@ -0,0 +1,202 @@
+package filemanager
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// grepRequest is representing the grep request
+// Pattern is a RE2 regular expression matched against every line,
+// Before and After are the number of context lines around a match,
+// binary files are only matched with IncludeBinary
+type grepRequest struct {
+	fileFilter
+	Pattern       string `json:"pattern"`
+	IgnoreCase    bool   `json:"ignore_case"`
+	Before        int    `json:"before"`
+	After         int    `json:"after"`
+	IncludeBinary bool   `json:"include_binary"`
+}
+
+// grepLine is representing a streamed matching or context line
+// Type is "match" or "context", TextOffset is the byte offset of the line
+// in the decoded utf-8 text of the file (the extracted text of documents),
+// which is not the offset in the stored bytes of utf-16 or latin-1 files,
+// and Submatches are the byte ranges of matches in the text
+type grepLine struct {
+	Type       string      `json:"type"`
+	File       string      `json:"file"`
+	Line       int         `json:"line"`
+	TextOffset int64       `json:"text_offset"`
+	Text       string      `json:"text"`
+	Submatches []highlight `json:"submatches,omitempty"`
+}
+
+// grepSummary is representing the last streamed record of a grep
+type grepSummary struct {
+	Type    string `json:"type"`
+	Matches int    `json:"matches"`
+	Files   int    `json:"files"`
+}
+
+// contextLine is a line kept for before context
+type contextLine struct {
+	line   int
+	offset int64
+	text   string
+}
+
+// grepper is matching lines of files and streaming them as ndjson
+type grepper struct {
+	pattern *regexp.Regexp
+	request *grepRequest
+	encoder *json.Encoder
+	flush   func()
+	matches int
+	files   int
+}
+
+// Grep is matching a regular expression against all the lines of the
+// selected files and streaming matching and context lines as ndjson
+// scanning stops as soon as the request is cancelled
+func (fm *fileManager) Grep(w http.ResponseWriter, r *http.Request) error {
+	grepRequest := &grepRequest{}
+	if err := decodeBody(r, grepRequest); err != nil {
+		return fmt.Errorf("grep request body decoding failed with %v", err)
+	}
+	if grepRequest.Pattern == "" {
+		return &statusError{code: http.StatusBadRequest, err: errors.New("grep pattern is empty")}
+	}
+	if grepRequest.Before < 0 || grepRequest.After < 0 {
+		return &statusError{code: http.StatusBadRequest, err: fmt.Errorf("invalid context lines before %v after %v", grepRequest.Before, grepRequest.After)}
+	}
+
+	expr := grepRequest.Pattern
+	if grepRequest.IgnoreCase {
+		expr = "(?i)" + expr
+	}
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		return &statusError{code: http.StatusBadRequest, err: fmt.Errorf("invalid grep pattern %v with error %v", grepRequest.Pattern, err)}
+	}
+
+	files, err := readDir(filesDir)
+	if err != nil {
+		return fmt.Errorf("error while reading files for grep %v", err)
+	}
+	files, err = grepRequest.selectPaths(files)
+	if err != nil {
+		return err
+	}
+	if !grepRequest.IncludeBinary {
+		if files, err = textPaths(files); err != nil {
+			return err
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	g := &grepper{
+		pattern: pattern,
+		request: grepRequest,
+		encoder: json.NewEncoder(w),
+		flush:   func() {},
+	}
+	if flusher, ok := w.(http.Flusher); ok {
+		g.flush = flusher.Flush
+	}
+
+	for _, fPath := range files {
+		if err := r.Context().Err(); err != nil {
+			return err
+		}
+		if err := g.grepFile(r, fPath); err != nil {
+			return err
+		}
+	}
+	return g.encoder.Encode(&grepSummary{Type: "summary", Matches: g.matches, Files: g.files})
+}
+
+// grepFile is streaming the matching lines of a file with their context
+func (g *grepper) grepFile(r *http.Request, fPath string) error {
+	file, err := openText(fPath)
+	if err != nil {
+		return fmt.Errorf("error while opening file %v with error %v", fPath, err)
+	}
+	defer file.Close()
+
+	name := relativeName(fPath)
+	before := make([]contextLine, 0, g.request.Before)
+	after := 0
+	matched := false
+	var offset int64
+	rdr := bufio.NewReader(file)
+	for lineNumber := 1; ; lineNumber++ {
+		if err := r.Context().Err(); err != nil {
+			return err
+		}
+
+		line, readErr := rdr.ReadString('\n')
+		if line == "" && readErr != nil {
+			if readErr != io.EOF {
+				return fmt.Errorf("error while reading from file %v with error %v", fPath, readErr)
+			}
+			break
+		}
+		text := strings.TrimRight(line, "\r\n")
+
+		if locs := g.pattern.FindAllStringIndex(text, -1); locs != nil {
+			for _, ctx := range before {
+				if err := g.send(&grepLine{Type: "context", File: name, Line: ctx.line, TextOffset: ctx.offset, Text: ctx.text}); err != nil {
+					return err
+				}
+			}
+			before = before[:0]
+
+			submatches := make([]highlight, 0, len(locs))
+			for _, loc := range locs {
+				submatches = append(submatches, highlight{Start: loc[0], End: loc[1]})
+			}
+			if err := g.send(&grepLine{Type: "match", File: name, Line: lineNumber, TextOffset: offset, Text: text, Submatches: submatches}); err != nil {
+				return err
+			}
+			g.matches++
+			matched = true
+			after = g.request.After
+		} else if after > 0 {
+			if err := g.send(&grepLine{Type: "context", File: name, Line: lineNumber, TextOffset: offset, Text: text}); err != nil {
+				return err
+			}
+			after--
+		} else if g.request.Before > 0 {
+			if len(before) == g.request.Before {
+				before = append(before[:0], before[1:]...)
+			}
+			before = append(before, contextLine{line: lineNumber, offset: offset, text: text})
+		}
+
+		offset += int64(len(line))
+		if readErr != nil {
+			break
+		}
+	}
+
+	if matched {
+		g.files++
+		g.flush()
+	}
+	return nil
+}
+
+// send is streaming a line as json
+func (g *grepper) send(line *grepLine) error {
+	if err := g.encoder.Encode(line); err != nil {
+		return fmt.Errorf("error while streaming grep line %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package filemanager
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// appendIndexDelay is how long appended files wait before they are indexed
+// again, every append within it is indexed at once
+const appendIndexDelay = time.Second
+
+// invertedIndex is mapping every word to the positions it occurs at in every file
+// the zero value is an empty index which is built from the files on first use
+// and kept up to date when files are added, updated or removed
+// updating is serializing updates, files are analysed without holding mu
+// so that searches are not blocked by it
+// trie is holding every indexed word with its count for the vocabulary
+// appended are the files waiting to be indexed again after appends
+type invertedIndex struct {
+	mu       sync.RWMutex
+	built    bool
+	docs     map[string]*fileAnalysis
+	postings map[string]map[string][]int
+	trie     *trieNode
+	words    int
+
+	updating  sync.Mutex
+	pendingMu sync.Mutex
+	appended  map[string]bool
+}
+
+// indexAnalyzer is the analyzer of the index (default tokenizer, no stop words)
+func indexAnalyzer() *analyzer {
+	a, _ := newAnalyzer(analysisOptions{})
+	return a
+}
+
+// ensureBuilt is building the index from all the files if not done yet
+func (idx *invertedIndex) ensureBuilt() error {
+	idx.mu.RLock()
+	built := idx.built
+	idx.mu.RUnlock()
+	if built {
+		return nil
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if idx.built {
+		return nil
+	}
+
+	analyses, err := analyzeFiles(indexAnalyzer())
+	if err != nil {
+		return fmt.Errorf("building search index failed with %v", err)
+	}
+
+	idx.docs = make(map[string]*fileAnalysis, len(analyses))
+	idx.postings = make(map[string]map[string][]int)
+	idx.trie = newTrieNode()
+	idx.words = 0
+	for _, analysis := range analyses {
+		idx.add(analysis)
+	}
+	idx.built = true
+	return nil
+}
+
+// update is (re)indexing a stored file after it was written
+// a binary file or a file which can not be read is dropped from the index
+func (idx *invertedIndex) update(fileName string) {
+	idx.updating.Lock()
+	defer idx.updating.Unlock()
+	idx.mu.RLock()
+	built := idx.built
+	idx.mu.RUnlock()
+	if !built {
+		return
+	}
+
+	filePath, err := getFilePath(fileName)
+	if err != nil {
+		return
+	}
+	var analysis *fileAnalysis
+	if meta, err := loadMeta(filePath); err == nil && meta.analysable() {
+		analysis, err = analyzeFile(filePath, indexAnalyzer())
+		if err != nil {
+			fmt.Printf("indexing file %v failed with %v", fileName, err)
+			analysis = nil
+		}
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.delete(relativeName(filePath))
+	if analysis != nil {
+		idx.add(analysis)
+	}
+}
+
+// updateAppended is indexing a file again a moment after lines were
+// appended to it, so that a file which is appended to line by line is not
+// analysed for every line
+func (idx *invertedIndex) updateAppended(fileName string) {
+	idx.pendingMu.Lock()
+	defer idx.pendingMu.Unlock()
+	if len(idx.appended) == 0 {
+		time.AfterFunc(appendIndexDelay, idx.updatePending)
+	}
+	if idx.appended == nil {
+		idx.appended = make(map[string]bool)
+	}
+	idx.appended[fileName] = true
+}
+
+// updatePending is indexing the files which were appended to
+func (idx *invertedIndex) updatePending() {
+	idx.pendingMu.Lock()
+	appended := idx.appended
+	idx.appended = nil
+	idx.pendingMu.Unlock()
+	for fileName := range appended {
+		idx.update(fileName)
+	}
+}
+
+// remove is dropping a removed file from the index
+func (idx *invertedIndex) remove(fileName string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if !idx.built {
+		return
+	}
+
+	filePath, err := getFilePath(fileName)
+	if err != nil {
+		return
+	}
+	idx.delete(relativeName(filePath))
+}
+
+// add is adding the words of an analysed file, the lock has to be held
+func (idx *invertedIndex) add(analysis *fileAnalysis) {
+	idx.docs[analysis.Name] = analysis
+	idx.words += analysis.Words
+	for position, tok := range analysis.Tokens {
+		docs, ok := idx.postings[tok.Word]
+		if !ok {
+			docs = make(map[string][]int)
+			idx.postings[tok.Word] = docs
+		}
+		docs[analysis.Name] = append(docs[analysis.Name], position)
+	}
+	for word, count := range analysis.Counts {
+		idx.trie.insert(word, count)
+	}
+}
+
+// delete is removing the words of a file, the lock has to be held
+func (idx *invertedIndex) delete(name string) {
+	analysis, ok := idx.docs[name]
+	if !ok {
+		return
+	}
+	for word, count := range analysis.Counts {
+		delete(idx.postings[word], name)
+		if len(idx.postings[word]) == 0 {
+			delete(idx.postings, word)
+		}
+		idx.trie.remove(word, count)
+	}
+	idx.words -= analysis.Words
+	delete(idx.docs, name)
+}
+
+// docNames is returning the names of all the indexed files in order
+// the lock has to be held
+func (idx *invertedIndex) docNames() []string {
+	names := make([]string, 0, len(idx.docs))
+	for name := range idx.docs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
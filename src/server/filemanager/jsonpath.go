@@ -0,0 +1,693 @@
+package filemanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// jsonPath is a parsed JSONPath query, a list of segments selecting the
+// values of the values selected by the segment before, starting at $
+//
+// the subset is $ (root), .name and ['name','other'] (fields), .* and [*]
+// (wildcard), ..name (recursive descent), [0,-1] (indexes), [start:end:step]
+// (slices) and [?(expression)] (filters), filters compare @ (the value) or
+// $ paths with literals using == != < <= > >= =~ (regular expression),
+// combined with && || ! and parentheses, a path alone tests existence
+type jsonPath []pathSegment
+
+// selector kinds of path segments
+const (
+	selectNames = iota
+	selectWildcard
+	selectIndexes
+	selectSlice
+	selectFilter
+)
+
+// pathSegment is a segment of a JSONPath, recursive segments (..) select
+// from the value and all the values below it
+type pathSegment struct {
+	kind      int
+	recursive bool
+	names     []string
+	indexes   []int
+	slice     [3]*int
+	filter    filterExpr
+}
+
+// jsonNode is a selected value and its normalized path
+type jsonNode struct {
+	path  string
+	value interface{}
+}
+
+// filterExpr is a filter expression evaluated for every candidate value (@)
+type filterExpr interface {
+	eval(current, root interface{}) bool
+}
+
+// logicalExpr is combining filter expressions with && (and) or || (or)
+type logicalExpr struct {
+	and         bool
+	left, right filterExpr
+}
+
+// notExpr is negating a filter expression
+type notExpr struct {
+	expr filterExpr
+}
+
+// comparisonExpr is comparing two operands, an operand alone (op is "")
+// is true when it exists
+type comparisonExpr struct {
+	left, right filterOperand
+	op          string
+	pattern     *regexp.Regexp
+}
+
+// filterOperand is a literal or a path relative to @ or $
+type filterOperand struct {
+	literal  interface{}
+	path     jsonPath
+	relative bool
+	isPath   bool
+}
+
+// pathParser is parsing a JSONPath query
+type pathParser struct {
+	query string
+	pos   int
+}
+
+// parseJSONPath is parsing a JSONPath query, the leading $ may be left out
+// (".name", "name.other" or "[0]")
+func parseJSONPath(query string) (jsonPath, error) {
+	query = strings.TrimSpace(query)
+	switch {
+	case query == "":
+		return nil, fmt.Errorf("query is empty")
+	case strings.HasPrefix(query, "$"):
+	case strings.HasPrefix(query, ".") || strings.HasPrefix(query, "["):
+		query = "$" + query
+	default:
+		query = "$." + query
+	}
+
+	p := &pathParser{query: query, pos: 1}
+	path, err := p.segments()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.query) {
+		return nil, p.errorf("unexpected %q", p.query[p.pos])
+	}
+	return path, nil
+}
+
+// errorf is returning a parse error at the current position
+func (p *pathParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid query %v at %v: %v", p.query, p.pos+1, fmt.Sprintf(format, args...))
+}
+
+// peek is returning the next character or 0 at the end
+func (p *pathParser) peek() byte {
+	if p.pos < len(p.query) {
+		return p.query[p.pos]
+	}
+	return 0
+}
+
+// skipSpaces is moving past spaces
+func (p *pathParser) skipSpaces() {
+	for p.pos < len(p.query) && p.query[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+// consume is moving past s when it is next
+func (p *pathParser) consume(s string) bool {
+	if strings.HasPrefix(p.query[p.pos:], s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+// expect is moving past s or failing
+func (p *pathParser) expect(s string) error {
+	p.skipSpaces()
+	if !p.consume(s) {
+		return p.errorf("%q expected", s)
+	}
+	return nil
+}
+
+// segments is parsing segments as long as they follow
+func (p *pathParser) segments() (jsonPath, error) {
+	path := jsonPath{}
+	for {
+		var segment pathSegment
+		var err error
+		switch {
+		case p.consume(".."):
+			if p.peek() == '[' {
+				p.pos++
+				segment, err = p.bracket()
+			} else {
+				segment, err = p.dotted()
+			}
+			segment.recursive = true
+		case p.consume("."):
+			segment, err = p.dotted()
+		case p.consume("["):
+			segment, err = p.bracket()
+		default:
+			return path, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		path = append(path, segment)
+	}
+}
+
+// dotted is parsing the name or wildcard following a dot
+func (p *pathParser) dotted() (pathSegment, error) {
+	if p.consume("*") {
+		return pathSegment{kind: selectWildcard}, nil
+	}
+	start := p.pos
+	for p.pos < len(p.query) && isNameChar(p.query[p.pos]) {
+		p.pos++
+	}
+	if start == p.pos {
+		return pathSegment{}, p.errorf("field name expected")
+	}
+	return pathSegment{kind: selectNames, names: []string{p.query[start:p.pos]}}, nil
+}
+
+// isNameChar is checking whether c can be part of a dotted field name
+func isNameChar(c byte) bool {
+	return isLetter(c) || (c >= '0' && c <= '9') || c == '_' || c == '-' || c >= 0x80
+}
+
+// bracket is parsing the selector following [ up to ]
+func (p *pathParser) bracket() (pathSegment, error) {
+	p.skipSpaces()
+	var segment pathSegment
+	switch c := p.peek(); {
+	case c == '*':
+		p.pos++
+		segment = pathSegment{kind: selectWildcard}
+	case c == '?':
+		p.pos++
+		if err := p.expect("("); err != nil {
+			return segment, err
+		}
+		filter, err := p.or()
+		if err != nil {
+			return segment, err
+		}
+		if err := p.expect(")"); err != nil {
+			return segment, err
+		}
+		segment = pathSegment{kind: selectFilter, filter: filter}
+	case c == '\'' || c == '"':
+		segment.kind = selectNames
+		for {
+			name, err := p.quoted()
+			if err != nil {
+				return segment, err
+			}
+			segment.names = append(segment.names, name)
+			p.skipSpaces()
+			if !p.consume(",") {
+				break
+			}
+			p.skipSpaces()
+		}
+	default:
+		var err error
+		if segment, err = p.indexes(); err != nil {
+			return segment, err
+		}
+	}
+	return segment, p.expect("]")
+}
+
+// indexes is parsing a list of indexes or a slice
+func (p *pathParser) indexes() (pathSegment, error) {
+	var parts [3]*int
+	part := 0
+	segment := pathSegment{kind: selectIndexes}
+	for {
+		p.skipSpaces()
+		if n, ok := p.integer(); ok {
+			parts[part] = &n
+		}
+		p.skipSpaces()
+		switch {
+		case p.consume(":"):
+			if segment.kind == selectIndexes && len(segment.indexes) > 0 || part == 2 {
+				return segment, p.errorf("invalid slice")
+			}
+			segment.kind = selectSlice
+			part++
+		case p.consume(","):
+			if segment.kind == selectSlice || parts[0] == nil {
+				return segment, p.errorf("invalid index list")
+			}
+			segment.indexes = append(segment.indexes, *parts[0])
+			parts[0] = nil
+		default:
+			if segment.kind == selectSlice {
+				if parts[2] != nil && *parts[2] == 0 {
+					return segment, p.errorf("slice step can not be 0")
+				}
+				segment.slice = parts
+				return segment, nil
+			}
+			if parts[0] == nil {
+				return segment, p.errorf("index expected")
+			}
+			segment.indexes = append(segment.indexes, *parts[0])
+			return segment, nil
+		}
+	}
+}
+
+// integer is parsing an integer if one follows
+func (p *pathParser) integer() (int, bool) {
+	start := p.pos
+	if p.peek() == '-' {
+		p.pos++
+	}
+	for p.pos < len(p.query) && p.query[p.pos] >= '0' && p.query[p.pos] <= '9' {
+		p.pos++
+	}
+	n, err := strconv.Atoi(p.query[start:p.pos])
+	if err != nil {
+		p.pos = start
+		return 0, false
+	}
+	return n, true
+}
+
+// quoted is parsing a string in single or double quotes
+func (p *pathParser) quoted() (string, error) {
+	quote := p.peek()
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.query) {
+		c := p.query[p.pos]
+		p.pos++
+		switch {
+		case c == quote:
+			return b.String(), nil
+		case c == '\\' && p.pos < len(p.query):
+			b.WriteByte(p.query[p.pos])
+			p.pos++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+// or is parsing expressions combined with ||
+func (p *pathParser) or() (filterExpr, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.skipSpaces(); p.consume("||"); p.skipSpaces() {
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpr{left: left, right: right}
+	}
+	return left, nil
+}
+
+// and is parsing expressions combined with &&
+func (p *pathParser) and() (filterExpr, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.skipSpaces(); p.consume("&&"); p.skipSpaces() {
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpr{and: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+// unary is parsing a negation, an expression in parentheses or a comparison
+func (p *pathParser) unary() (filterExpr, error) {
+	p.skipSpaces()
+	if p.peek() == '!' && !strings.HasPrefix(p.query[p.pos:], "!=") {
+		p.pos++
+		expr, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{expr: expr}, nil
+	}
+	if p.consume("(") {
+		expr, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		return expr, p.expect(")")
+	}
+
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	comparison := &comparisonExpr{left: left}
+	p.skipSpaces()
+	for _, op := range []string{"==", "!=", "<=", ">=", "=~", "<", ">"} {
+		if p.consume(op) {
+			comparison.op = op
+			break
+		}
+	}
+	if comparison.op == "" {
+		if !left.isPath {
+			return nil, p.errorf("comparison expected")
+		}
+		return comparison, nil
+	}
+	p.skipSpaces()
+	if comparison.right, err = p.operand(); err != nil {
+		return nil, err
+	}
+	if comparison.op == "=~" {
+		pattern, ok := comparison.right.literal.(string)
+		if comparison.right.isPath || !ok {
+			return nil, p.errorf("=~ needs a regular expression string")
+		}
+		if comparison.pattern, err = regexp.Compile(pattern); err != nil {
+			return nil, p.errorf("invalid regular expression %v", err)
+		}
+	}
+	return comparison, nil
+}
+
+// operand is parsing a path starting at @ or $ or a literal
+func (p *pathParser) operand() (filterOperand, error) {
+	p.skipSpaces()
+	switch c := p.peek(); {
+	case c == '@' || c == '$':
+		p.pos++
+		path, err := p.segments()
+		return filterOperand{path: path, relative: c == '@', isPath: true}, err
+	case c == '\'' || c == '"':
+		s, err := p.quoted()
+		return filterOperand{literal: s}, err
+	case p.consume("true"):
+		return filterOperand{literal: true}, nil
+	case p.consume("false"):
+		return filterOperand{literal: false}, nil
+	case p.consume("null"):
+		return filterOperand{literal: nil}, nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.query) && strings.IndexByte("+-0123456789.eE", p.query[p.pos]) >= 0 {
+		p.pos++
+	}
+	f, err := strconv.ParseFloat(p.query[start:p.pos], 64)
+	if err != nil {
+		p.pos = start
+		return filterOperand{}, p.errorf("value expected")
+	}
+	return filterOperand{literal: f}, nil
+}
+
+// eval is combining the results of both expressions
+func (e *logicalExpr) eval(current, root interface{}) bool {
+	if e.and {
+		return e.left.eval(current, root) && e.right.eval(current, root)
+	}
+	return e.left.eval(current, root) || e.right.eval(current, root)
+}
+
+// eval is negating the result of the expression
+func (e *notExpr) eval(current, root interface{}) bool {
+	return !e.expr.eval(current, root)
+}
+
+// value is returning the value of an operand and whether it exists
+func (o filterOperand) value(current, root interface{}) (interface{}, bool) {
+	if !o.isPath {
+		return o.literal, true
+	}
+	start := root
+	if o.relative {
+		start = current
+	}
+	nodes := o.path.eval(start)
+	if len(nodes) == 0 {
+		return nil, false
+	}
+	return nodes[0].value, true
+}
+
+// eval is comparing the operands, values of different types are only not equal
+func (e *comparisonExpr) eval(current, root interface{}) bool {
+	left, ok := e.left.value(current, root)
+	if !ok {
+		return false
+	}
+	if e.op == "" {
+		return true
+	}
+	right, ok := e.right.value(current, root)
+	if !ok {
+		return false
+	}
+
+	if e.op == "=~" {
+		s, ok := left.(string)
+		return ok && e.pattern.MatchString(s)
+	}
+	if l, ok := jsonNumber(left); ok {
+		if r, ok := jsonNumber(right); ok {
+			return compareOrdered(e.op, l < r, l == r)
+		}
+	}
+	if l, ok := left.(string); ok {
+		if r, ok := right.(string); ok {
+			return compareOrdered(e.op, l < r, l == r)
+		}
+	}
+	switch e.op {
+	case "==":
+		return jsonEqual(left, right)
+	case "!=":
+		return !jsonEqual(left, right)
+	}
+	return false
+}
+
+// compareOrdered is the result of op for ordered values
+func compareOrdered(op string, less, equal bool) bool {
+	switch op {
+	case "==":
+		return equal
+	case "!=":
+		return !equal
+	case "<":
+		return less
+	case "<=":
+		return less || equal
+	case ">":
+		return !less && !equal
+	case ">=":
+		return !less
+	}
+	return false
+}
+
+// jsonNumber is returning the value of a decoded json number
+func jsonNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// jsonEqual is comparing decoded json values by their encoding
+func jsonEqual(a, b interface{}) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(ja) == string(jb)
+}
+
+// eval is returning the values selected by the path in document order,
+// the fields of objects are taken in the order of their names
+func (path jsonPath) eval(root interface{}) []jsonNode {
+	nodes := []jsonNode{{path: "$", value: root}}
+	for _, segment := range path {
+		if segment.recursive {
+			nodes = descendants(nodes)
+		}
+		selected := []jsonNode{}
+		for _, node := range nodes {
+			selected = segment.selectFrom(node, root, selected)
+		}
+		nodes = selected
+	}
+	return nodes
+}
+
+// descendants is returning the nodes and all the values below them
+func descendants(nodes []jsonNode) []jsonNode {
+	all := []jsonNode{}
+	var walk func(node jsonNode)
+	walk = func(node jsonNode) {
+		all = append(all, node)
+		for _, child := range children(node) {
+			walk(child)
+		}
+	}
+	for _, node := range nodes {
+		walk(node)
+	}
+	return all
+}
+
+// children is returning the fields of an object or the elements of an array
+func children(node jsonNode) []jsonNode {
+	switch v := node.value.(type) {
+	case map[string]interface{}:
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		nodes := make([]jsonNode, 0, len(v))
+		for _, name := range names {
+			nodes = append(nodes, jsonNode{path: fieldPath(node.path, name), value: v[name]})
+		}
+		return nodes
+	case []interface{}:
+		nodes := make([]jsonNode, 0, len(v))
+		for i, value := range v {
+			nodes = append(nodes, jsonNode{path: node.path + "[" + strconv.Itoa(i) + "]", value: value})
+		}
+		return nodes
+	}
+	return nil
+}
+
+// selectFrom is adding the values the segment selects from node to selected
+func (segment pathSegment) selectFrom(node jsonNode, root interface{}, selected []jsonNode) []jsonNode {
+	switch segment.kind {
+	case selectNames:
+		if object, ok := node.value.(map[string]interface{}); ok {
+			for _, name := range segment.names {
+				if value, ok := object[name]; ok {
+					selected = append(selected, jsonNode{path: fieldPath(node.path, name), value: value})
+				}
+			}
+		}
+	case selectWildcard:
+		selected = append(selected, children(node)...)
+	case selectIndexes:
+		if array, ok := node.value.([]interface{}); ok {
+			for _, i := range segment.indexes {
+				if i < 0 {
+					i += len(array)
+				}
+				if i >= 0 && i < len(array) {
+					selected = append(selected, jsonNode{path: node.path + "[" + strconv.Itoa(i) + "]", value: array[i]})
+				}
+			}
+		}
+	case selectSlice:
+		if array, ok := node.value.([]interface{}); ok {
+			for _, i := range sliceIndexes(segment.slice, len(array)) {
+				selected = append(selected, jsonNode{path: node.path + "[" + strconv.Itoa(i) + "]", value: array[i]})
+			}
+		}
+	case selectFilter:
+		for _, child := range children(node) {
+			if segment.filter.eval(child.value, root) {
+				selected = append(selected, child)
+			}
+		}
+	}
+	return selected
+}
+
+// sliceIndexes is returning the indexes of a slice [start:end:step] of an
+// array of length n, negative values count from the end like in python
+func sliceIndexes(slice [3]*int, n int) []int {
+	step := 1
+	if slice[2] != nil {
+		step = *slice[2]
+	}
+	bound := func(v *int, def int) int {
+		if v == nil {
+			return def
+		}
+		i := *v
+		if i < 0 {
+			i += n
+		}
+		low, high := 0, n
+		if step < 0 {
+			low, high = -1, n-1
+		}
+		if i < low {
+			return low
+		}
+		if i > high {
+			return high
+		}
+		return i
+	}
+
+	indexes := []int{}
+	if step > 0 {
+		for i := bound(slice[0], 0); i < bound(slice[1], n); i += step {
+			indexes = append(indexes, i)
+		}
+		return indexes
+	}
+	for i := bound(slice[0], n-1); i > bound(slice[1], -1); i += step {
+		indexes = append(indexes, i)
+	}
+	return indexes
+}
+
+// fieldPath is appending a field to a normalized path, names which are
+// not identifiers are quoted
+func fieldPath(path, name string) string {
+	identifier := name != ""
+	for i := 0; i < len(name); i++ {
+		if !isNameChar(name[i]) || name[i] == '-' || (i == 0 && name[i] >= '0' && name[i] <= '9') {
+			identifier = false
+			break
+		}
+	}
+	if identifier {
+		return path + "." + name
+	}
+	return path + "['" + strings.ReplaceAll(strings.ReplaceAll(name, `\`, `\\`), "'", `\'`) + "']"
+}
@@ -0,0 +1,65 @@
+package filemanager
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_jsonPath_eval(t *testing.T) {
+	document := `{"store": {"book": [
+		{"title": "Sayings", "price": 8.95, "tags": ["old"]},
+		{"title": "Sword", "price": 12.99, "isbn": "0-553"},
+		{"title": "Moby Dick", "price": 8.99, "isbn": "0-395"}
+	], "bicycle": {"color": "red", "price": 19.95}}, "max": 10, "odd key": true}`
+	var root interface{}
+	decoder := json.NewDecoder(strings.NewReader(document))
+	decoder.UseNumber()
+	if err := decoder.Decode(&root); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "$.store.book[0].title", want: []string{"$.store.book[0].title"}},
+		{query: "store.bicycle['color','price']", want: []string{"$.store.bicycle.color", "$.store.bicycle.price"}},
+		{query: "$['odd key']", want: []string{"$['odd key']"}},
+		{query: "$.store.*", want: []string{"$.store.bicycle", "$.store.book"}},
+		{query: "$..price", want: []string{"$.store.bicycle.price", "$.store.book[0].price", "$.store.book[1].price", "$.store.book[2].price"}},
+		{query: "$.store.book[-1].title", want: []string{"$.store.book[2].title"}},
+		{query: "$.store.book[0,2].price", want: []string{"$.store.book[0].price", "$.store.book[2].price"}},
+		{query: "$.store.book[1:].title", want: []string{"$.store.book[1].title", "$.store.book[2].title"}},
+		{query: "$.store.book[::-2].title", want: []string{"$.store.book[2].title", "$.store.book[0].title"}},
+		{query: "$.store.book[?(@.isbn)].title", want: []string{"$.store.book[1].title", "$.store.book[2].title"}},
+		{query: "$.store.book[?(@.price < $.max && !(@.title == 'Sayings'))].title", want: []string{"$.store.book[2].title"}},
+		{query: "$..book[?(@.title =~ '^S' || @.price > 12)].price", want: []string{"$.store.book[0].price", "$.store.book[1].price"}},
+		{query: "$.store.book[?(@.tags[0] == 'old')].title", want: []string{"$.store.book[0].title"}},
+		{query: "$.missing[0]", want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			path, err := parseJSONPath(tt.query)
+			if err != nil {
+				t.Fatalf("parseJSONPath() error = %v", err)
+			}
+			got := []string{}
+			for _, node := range path.eval(root) {
+				got = append(got, node.path)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("jsonPath.eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseJSONPath_errors(t *testing.T) {
+	for _, query := range []string{"", "$.", "$[", "$['a'", "$[1:2:0]", "$[?(@.a ==)]", "$[?(@.a =~ '(')]", "$.a b", "$[?('x')]"} {
+		if _, err := parseJSONPath(query); err == nil {
+			t.Errorf("parseJSONPath(%q) error = nil", query)
+		}
+	}
+}
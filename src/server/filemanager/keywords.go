@@ -0,0 +1,230 @@
+package filemanager
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// defaultKeywordLimit is the number of keywords or similar files returned by default
+const defaultKeywordLimit = 10
+
+// keywordsRequest is representing the tf-idf keywords request
+// Limit is the number of keywords per file
+type keywordsRequest struct {
+	analysisOptions
+	Limit int `json:"limit"`
+}
+
+// keyword is representing a word of a file with its tf-idf weight
+type keyword struct {
+	Word  string  `json:"word"`
+	Count int     `json:"count"`
+	TFIDF float64 `json:"tf_idf"`
+}
+
+// fileKeywords is representing the keywords of a file
+type fileKeywords struct {
+	Name     string    `json:"name"`
+	Keywords []keyword `json:"keywords"`
+}
+
+// keywordsResponse is representing the keywords of all the files
+type keywordsResponse struct {
+	Files []fileKeywords `json:"files"`
+}
+
+// similarRequest is representing the similar files request
+// either a stored File or a Text snippet is compared with the files
+type similarRequest struct {
+	analysisOptions
+	File  string `json:"file"`
+	Text  string `json:"text"`
+	Limit int    `json:"limit"`
+}
+
+// similarFile is representing a file with its cosine similarity
+type similarFile struct {
+	Name  string  `json:"name"`
+	Score float64 `json:"score"`
+}
+
+// similarResponse is representing the files most similar first
+type similarResponse struct {
+	Files []similarFile `json:"files"`
+}
+
+// Keywords is returning the words with the highest tf-idf weight of every file
+func (fm *fileManager) Keywords(r *http.Request) (interface{}, error) {
+	keywordsRequest := &keywordsRequest{}
+	if err := decodeBody(r, keywordsRequest); err != nil {
+		return nil, fmt.Errorf("keywords request body decoding failed with %v", err)
+	}
+	if keywordsRequest.Limit <= 0 {
+		keywordsRequest.Limit = defaultKeywordLimit
+	}
+
+	analyzer, err := newAnalyzer(keywordsRequest.analysisOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	analyses, err := analyzeFiles(analyzer)
+	if err != nil {
+		return nil, err
+	}
+
+	df := documentFrequencies(analyses)
+	keywordsResponse := &keywordsResponse{
+		Files: make([]fileKeywords, 0, len(analyses)),
+	}
+	for _, analysis := range analyses {
+		weights := tfidfVector(analysis, df, len(analyses))
+		keywords := make([]keyword, 0, len(weights))
+		for word, weight := range weights {
+			keywords = append(keywords, keyword{
+				Word:  word,
+				Count: analysis.Counts[word],
+				TFIDF: weight,
+			})
+		}
+
+		sort.Slice(keywords, func(i, j int) bool {
+			if keywords[i].TFIDF != keywords[j].TFIDF {
+				return keywords[i].TFIDF > keywords[j].TFIDF
+			}
+			return keywords[i].Word < keywords[j].Word
+		})
+		if len(keywords) > keywordsRequest.Limit {
+			keywords = keywords[:keywordsRequest.Limit]
+		}
+
+		keywordsResponse.Files = append(keywordsResponse.Files, fileKeywords{
+			Name:     analysis.Name,
+			Keywords: keywords,
+		})
+	}
+	return keywordsResponse, nil
+}
+
+// Similar is returning the files most similar to a file or a text snippet
+// ranked by the cosine similarity of their tf-idf vectors
+func (fm *fileManager) Similar(r *http.Request) (interface{}, error) {
+	similarRequest := &similarRequest{}
+	if err := decodeBody(r, similarRequest); err != nil {
+		return nil, fmt.Errorf("similar request body decoding failed with %v", err)
+	}
+	if (similarRequest.File == "") == (similarRequest.Text == "") {
+		return nil, fmt.Errorf("either file or text has to be given")
+	}
+	if similarRequest.Limit <= 0 {
+		similarRequest.Limit = defaultKeywordLimit
+	}
+
+	analyzer, err := newAnalyzer(similarRequest.analysisOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	analyses, err := analyzeFiles(analyzer)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := similarityTarget(similarRequest, analyses, analyzer)
+	if err != nil {
+		return nil, err
+	}
+
+	df := documentFrequencies(analyses)
+	targetVector := tfidfVector(target, df, len(analyses))
+	similarResponse := &similarResponse{
+		Files: []similarFile{},
+	}
+	for _, analysis := range analyses {
+		if analysis.Name == similarRequest.File {
+			continue
+		}
+		similarResponse.Files = append(similarResponse.Files, similarFile{
+			Name:  analysis.Name,
+			Score: cosineSimilarity(targetVector, tfidfVector(analysis, df, len(analyses))),
+		})
+	}
+
+	sort.SliceStable(similarResponse.Files, func(i, j int) bool {
+		return similarResponse.Files[i].Score > similarResponse.Files[j].Score
+	})
+	if len(similarResponse.Files) > similarRequest.Limit {
+		similarResponse.Files = similarResponse.Files[:similarRequest.Limit]
+	}
+	return similarResponse, nil
+}
+
+// similarityTarget is returning the analysis of the file or text to compare with
+// a stored file which is not selected by the filter is analysed on its own
+func similarityTarget(request *similarRequest, analyses []*fileAnalysis, a *analyzer) (*fileAnalysis, error) {
+	if request.Text != "" {
+		return analyzeReader("", strings.NewReader(request.Text), a)
+	}
+
+	for _, analysis := range analyses {
+		if analysis.Name == request.File {
+			return analysis, nil
+		}
+	}
+
+	filePath, err := getFilePath(request.File)
+	if err != nil {
+		return nil, err
+	}
+	return analyzeFile(filePath, a)
+}
+
+// documentFrequencies is counting the number of files every word occurs in
+func documentFrequencies(analyses []*fileAnalysis) map[string]int {
+	df := make(map[string]int)
+	for _, analysis := range analyses {
+		for word := range analysis.Counts {
+			df[word]++
+		}
+	}
+	return df
+}
+
+// inverseDocumentFrequency is the smoothed idf of a word occurring in df of n files
+// words which occur in every file still get a small positive weight
+func inverseDocumentFrequency(df, n int) float64 {
+	return math.Log(float64(1+n)/float64(1+df)) + 1
+}
+
+// tfidfVector is returning the tf-idf weight of every word of an analysis
+// term frequency is the count of the word relative to all words of the file
+func tfidfVector(analysis *fileAnalysis, df map[string]int, n int) map[string]float64 {
+	weights := make(map[string]float64, len(analysis.Counts))
+	if analysis.Words == 0 {
+		return weights
+	}
+	for word, count := range analysis.Counts {
+		tf := float64(count) / float64(analysis.Words)
+		weights[word] = tf * inverseDocumentFrequency(df[word], n)
+	}
+	return weights
+}
+
+// cosineSimilarity is returning the cosine of the angle between two vectors
+func cosineSimilarity(a, b map[string]float64) float64 {
+	var dot, normA, normB float64
+	for word, weight := range a {
+		dot += weight * b[word]
+		normA += weight * weight
+	}
+	for _, weight := range b {
+		normB += weight * weight
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
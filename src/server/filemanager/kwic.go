@@ -0,0 +1,169 @@
+package filemanager
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// defaultKWICContext is the number of context words on each side by default
+const defaultKWICContext = 5
+
+// kwicRequest is representing the keyword in context (concordance) request
+// Context is the number of words shown on each side of the word
+// Limit is the maximum number of occurrences (all when 0)
+type kwicRequest struct {
+	analysisOptions
+	Word    string `json:"word"`
+	Context int    `json:"context"`
+	Limit   int    `json:"limit"`
+}
+
+// kwicOccurrence is representing an occurrence of the word with its context
+// words are shown as they are written in the file
+type kwicOccurrence struct {
+	Line  int    `json:"line"`
+	Left  string `json:"left"`
+	Word  string `json:"word"`
+	Right string `json:"right"`
+}
+
+// kwicFile is representing the occurrences of the word in a file
+type kwicFile struct {
+	Name        string           `json:"name"`
+	Occurrences []kwicOccurrence `json:"occurrences"`
+}
+
+// kwicResponse is representing the occurrences grouped by file
+type kwicResponse struct {
+	Word  string     `json:"word"`
+	Total int        `json:"total"`
+	Files []kwicFile `json:"files"`
+}
+
+// surfaceWord is a word as written in a file with its normalized form
+type surfaceWord struct {
+	text string
+	term string
+	line int
+}
+
+// KWIC is returning every occurrence of a word with the words around it
+// grouped by file, words are matched with the same tokenizer as word counts
+func (fm *fileManager) KWIC(r *http.Request) (interface{}, error) {
+	kwicRequest := &kwicRequest{}
+	if err := decodeBody(r, kwicRequest); err != nil {
+		return nil, fmt.Errorf("kwic request body decoding failed with %v", err)
+	}
+	if kwicRequest.Context < 0 {
+		return nil, fmt.Errorf("invalid context %v", kwicRequest.Context)
+	}
+	if kwicRequest.Context == 0 {
+		kwicRequest.Context = defaultKWICContext
+	}
+
+	analyzer, err := newAnalyzer(kwicRequest.analysisOptions)
+	if err != nil {
+		return nil, err
+	}
+	term, err := analyzer.term(kwicRequest.Word)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := readDir(filesDir)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading files for kwic %v", err)
+	}
+	files, err = kwicRequest.selectPaths(files)
+	if err != nil {
+		return nil, err
+	}
+	if !kwicRequest.IncludeBinary {
+		if files, err = textPaths(files); err != nil {
+			return nil, err
+		}
+	}
+
+	kwicResponse := &kwicResponse{
+		Word:  term,
+		Files: []kwicFile{},
+	}
+	for _, fPath := range files {
+		words, err := surfaceWords(fPath, analyzer)
+		if err != nil {
+			return nil, err
+		}
+
+		occurrences := []kwicOccurrence{}
+		for i, word := range words {
+			if word.term != term {
+				continue
+			}
+			if kwicRequest.Limit > 0 && kwicResponse.Total == kwicRequest.Limit {
+				break
+			}
+			occurrences = append(occurrences, kwicOccurrence{
+				Line:  word.line,
+				Left:  joinSurface(words, i-kwicRequest.Context, i),
+				Word:  word.text,
+				Right: joinSurface(words, i+1, i+1+kwicRequest.Context),
+			})
+			kwicResponse.Total++
+		}
+		if len(occurrences) > 0 {
+			kwicResponse.Files = append(kwicResponse.Files, kwicFile{
+				Name:        relativeName(fPath),
+				Occurrences: occurrences,
+			})
+		}
+	}
+	return kwicResponse, nil
+}
+
+// surfaceWords is returning all the words of a file (stop words included)
+// as they are written together with their normalized form
+func surfaceWords(fPath string, a *analyzer) ([]surfaceWord, error) {
+	file, err := openText(fPath)
+	if err != nil {
+		return nil, fmt.Errorf("error while opening file %v with error %v", fPath, err)
+	}
+	defer file.Close()
+
+	words := []surfaceWord{}
+	rdr := bufio.NewReader(file)
+	for lineNumber := 1; ; lineNumber++ {
+		line, err := rdr.ReadString('\n')
+		for _, tok := range a.words(line) {
+			words = append(words, surfaceWord{
+				text: line[tok.Start:tok.End],
+				term: tok.Text,
+				line: lineNumber,
+			})
+		}
+		if err != nil {
+			if err != io.EOF {
+				return nil, fmt.Errorf("error while reading from file %v with error %v", fPath, err)
+			}
+			break
+		}
+	}
+	return words, nil
+}
+
+// joinSurface is joining the words from (inclusive) to (exclusive) with spaces
+func joinSurface(words []surfaceWord, from, to int) string {
+	if from < 0 {
+		from = 0
+	}
+	if to > len(words) {
+		to = len(words)
+	}
+	texts := make([]string, 0, to-from)
+	for _, word := range words[from:to] {
+		texts = append(texts, word.text)
+	}
+	return strings.Join(texts, " ")
+}
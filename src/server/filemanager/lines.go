@@ -0,0 +1,405 @@
+package filemanager
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// linesDir is the directory where the line index of every text file is stored
+// the index of "a/b.txt" is "a/b.txt.json"
+var linesDir = "../lines"
+
+// lineIndexInterval is the number of lines between two offsets of a line
+// index, a read starts at most this many lines before the first wanted line
+const lineIndexInterval = 1000
+
+// defaultReadLines is the number of lines of head, tail and a range without end
+const defaultReadLines = 10
+
+// maxReadLines is the largest number of lines of a single read
+const maxReadLines = 10000
+
+// lineIndex is representing a sparse index of the lines of a file
+// Offsets[i] is where line i*lineIndexInterval+1 starts, Newlines is the
+// number of line endings and End is the offset after the last one
+// Size is the size of the file when it was indexed, the index of a file
+// which grew is extended from there
+type lineIndex struct {
+	Size     int64   `json:"size"`
+	Newlines int64   `json:"newlines"`
+	End      int64   `json:"end"`
+	Offsets  []int64 `json:"offsets"`
+}
+
+// linesRequest is representing the line range request
+// Start and End are line numbers starting at 1, both are included
+type linesRequest struct {
+	Name  string `json:"name"`
+	Start int64  `json:"start"`
+	End   int64  `json:"end"`
+}
+
+// headRequest is representing the first or last lines request
+type headRequest struct {
+	Name  string `json:"name"`
+	Lines int64  `json:"lines"`
+}
+
+// linesResponse is representing lines of a file, Start is the number of
+// the first line and Total is the number of lines of the file
+type linesResponse struct {
+	Name  string   `json:"name"`
+	Start int64    `json:"start"`
+	Total int64    `json:"total"`
+	Lines []string `json:"lines"`
+}
+
+// lines is returning the number of lines, the last line may have no line ending
+func (idx *lineIndex) lines() int64 {
+	if idx.Size > idx.End {
+		return idx.Newlines + 1
+	}
+	return idx.Newlines
+}
+
+// Lines is returning the lines from start to end of a file
+func (fm *fileManager) Lines(r *http.Request) (interface{}, error) {
+	linesRequest := &linesRequest{}
+	if err := decodeBody(r, linesRequest); err != nil {
+		return nil, fmt.Errorf("lines request body decoding failed with %v", err)
+	}
+	if linesRequest.End == 0 {
+		linesRequest.End = linesRequest.Start + defaultReadLines - 1
+	}
+	switch {
+	case linesRequest.Start < 1:
+		return nil, &statusError{code: http.StatusBadRequest, err: fmt.Errorf("invalid start %v, lines start at 1", linesRequest.Start)}
+	case linesRequest.End < linesRequest.Start:
+		return nil, &statusError{code: http.StatusBadRequest, err: fmt.Errorf("end %v is before start %v", linesRequest.End, linesRequest.Start)}
+	case linesRequest.End-linesRequest.Start >= maxReadLines:
+		return nil, &statusError{code: http.StatusBadRequest, err: fmt.Errorf("at most %v lines can be read at once", maxReadLines)}
+	}
+
+	fPath, meta, idx, err := openLines(linesRequest.Name)
+	if err != nil {
+		return nil, err
+	}
+	return readLineRange(fPath, meta, idx, linesRequest.Start, linesRequest.End)
+}
+
+// Head is returning the first lines of a file
+func (fm *fileManager) Head(r *http.Request) (interface{}, error) {
+	headRequest, err := decodeHeadRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	fPath, meta, idx, err := openLines(headRequest.Name)
+	if err != nil {
+		return nil, err
+	}
+	return readLineRange(fPath, meta, idx, 1, headRequest.Lines)
+}
+
+// Tail is returning the last lines of a file
+func (fm *fileManager) Tail(r *http.Request) (interface{}, error) {
+	headRequest, err := decodeHeadRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	fPath, meta, idx, err := openLines(headRequest.Name)
+	if err != nil {
+		return nil, err
+	}
+	start := idx.lines() - headRequest.Lines + 1
+	if start < 1 {
+		start = 1
+	}
+	return readLineRange(fPath, meta, idx, start, idx.lines())
+}
+
+// decodeHeadRequest is decoding a head or tail request, 10 lines are read
+// when the number of lines is not given
+func decodeHeadRequest(r *http.Request) (*headRequest, error) {
+	headRequest := &headRequest{}
+	if err := decodeBody(r, headRequest); err != nil {
+		return nil, fmt.Errorf("lines request body decoding failed with %v", err)
+	}
+	if headRequest.Lines == 0 {
+		headRequest.Lines = defaultReadLines
+	}
+	if headRequest.Lines < 0 || headRequest.Lines > maxReadLines {
+		return nil, &statusError{code: http.StatusBadRequest, err: fmt.Errorf("invalid number of lines %v, at most %v lines can be read at once", headRequest.Lines, maxReadLines)}
+	}
+	return headRequest, nil
+}
+
+// openLines is returning the path, metadata and line index of a file
+// which can be read line by line
+func openLines(name string) (string, *fileMeta, *lineIndex, error) {
+	fPath, err := getFilePath(name)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	meta, err := loadMeta(fPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil, nil, &statusError{code: http.StatusNotFound, err: fmt.Errorf("file does not exist %v", name)}
+	}
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if !lineIndexed(meta) {
+		return "", nil, nil, &statusError{code: http.StatusUnsupportedMediaType, err: fmt.Errorf("file %v of type %v can not be read line by line", name, meta.ContentType)}
+	}
+	idx, err := readLineIndex(fPath, meta.Encoding)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return fPath, meta, idx, nil
+}
+
+// readLineRange is reading the lines from start to end, the file is read
+// from the indexed line before start and pii is redacted when the pii
+// action is redact
+// lines written after the file was indexed are not read
+func readLineRange(fPath string, meta *fileMeta, idx *lineIndex, start, end int64) (*linesResponse, error) {
+	response := &linesResponse{Name: relativeName(fPath), Start: start, Total: idx.lines(), Lines: []string{}}
+	if end > response.Total {
+		end = response.Total
+	}
+	if start > end {
+		return response, nil
+	}
+
+	scanner, err := redactingScanner()
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(fPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	block := (start - 1) / lineIndexInterval
+	offset := idx.Offsets[block]
+	rdr := bufio.NewReader(io.NewSectionReader(file, offset, idx.Size-offset))
+	newline := newlineOf(meta.Encoding)
+	for line := block*lineIndexInterval + 1; line <= end; line++ {
+		text, err := readLine(rdr, newline)
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("error while reading file %v with error %v", response.Name, err)
+		}
+		if line >= start {
+			response.Lines = append(response.Lines, scanner.redactText(lineText(text, meta.Encoding, line == 1)))
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+	return response, nil
+}
+
+// readLine is reading a line up to and including its line feed
+func readLine(rdr *bufio.Reader, newline []byte) ([]byte, error) {
+	if len(newline) == 1 {
+		return rdr.ReadBytes('\n')
+	}
+	line := []byte{}
+	unit := make([]byte, len(newline))
+	for {
+		n, err := io.ReadFull(rdr, unit)
+		line = append(line, unit[:n]...)
+		if err == io.ErrUnexpectedEOF {
+			err = io.EOF
+		}
+		if err != nil || bytes.Equal(unit, newline) {
+			return line, err
+		}
+	}
+}
+
+// lineText is returning a line of a file as utf-8 without its line ending
+// and the byte order mark of the first line
+func lineText(line []byte, encoding string, first bool) string {
+	text := string(line)
+	if encoding != "" && encoding != encodingUTF8 {
+		text, _ = decodeText(line, encoding)
+	}
+	text = strings.TrimRight(text, "\r\n")
+	if first {
+		return strings.TrimPrefix(text, "\ufeff")
+	}
+	return text
+}
+
+// newlineOf is returning the line feed of an encoding, it is a 16 bit unit
+// in utf-16
+func newlineOf(encoding string) []byte {
+	switch encoding {
+	case encodingUTF16LE:
+		return []byte{'\n', 0}
+	case encodingUTF16BE:
+		return []byte{0, '\n'}
+	}
+	return []byte{'\n'}
+}
+
+// lineIndexed is checking whether the lines of a file are indexed
+func lineIndexed(meta *fileMeta) bool {
+	return meta.Text
+}
+
+// linesPath is returning the path of the line index of a file
+func linesPath(name string) (string, error) {
+	linesPath, err := filepath.Abs(filepath.Join(linesDir, name+".json"))
+	if err != nil {
+		return "", fmt.Errorf("error while creating line index path %v", err)
+	}
+	return linesPath, nil
+}
+
+// writeLineIndex is storing the line index of a file which was written, the
+// stored index is extended when lines were appended and built again otherwise
+// the index of a file which is not indexed any more is removed
+func writeLineIndex(meta *fileMeta, appended bool) error {
+	if !lineIndexed(meta) {
+		return removeLineIndex(meta.Name)
+	}
+	fPath, err := getFilePath(meta.Name)
+	if err != nil {
+		return err
+	}
+	linesPath, err := linesPath(meta.Name)
+	if err != nil {
+		return err
+	}
+	idx := &lineIndex{Offsets: []int64{0}}
+	if appended {
+		if stored := storedLineIndex(linesPath); stored != nil {
+			idx = stored
+		}
+	}
+	if err := idx.indexFile(fPath, meta.Encoding); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(linesPath), 0755); err != nil {
+		return fmt.Errorf("creating line index directory failed with %v", err)
+	}
+	if err := ioutil.WriteFile(linesPath, data, 0644); err != nil {
+		return fmt.Errorf("writing line index of %v failed with %v", meta.Name, err)
+	}
+	return nil
+}
+
+// removeLineIndex is removing the line index of a file
+func removeLineIndex(name string) error {
+	linesPath, err := linesPath(name)
+	if err != nil {
+		return err
+	}
+	if err := removeSidecar(linesDir, linesPath); err != nil {
+		return fmt.Errorf("removing line index of %v failed with %v", name, err)
+	}
+	return nil
+}
+
+// readLineIndex is returning the line index of a stored file (fPath as given
+// by getFilePath), the stored index is extended in memory when the file grew
+// since it was written, it is not written back
+func readLineIndex(fPath, encoding string) (*lineIndex, error) {
+	linesPath, err := linesPath(relativeName(fPath))
+	if err != nil {
+		return nil, err
+	}
+	idx := storedLineIndex(linesPath)
+	if idx == nil {
+		idx = &lineIndex{Offsets: []int64{0}}
+	}
+	if err := idx.indexFile(fPath, encoding); err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+// storedLineIndex is returning the stored line index, nil when there is none
+func storedLineIndex(linesPath string) *lineIndex {
+	data, err := ioutil.ReadFile(linesPath)
+	if err != nil {
+		return nil
+	}
+	idx := &lineIndex{}
+	if err := json.Unmarshal(data, idx); err != nil || len(idx.Offsets) == 0 {
+		return nil
+	}
+	return idx
+}
+
+// indexFile is indexing the lines of a file written after the indexed size,
+// the index is built again when the file shrank
+func (idx *lineIndex) indexFile(fPath, encoding string) error {
+	file, err := os.Open(fPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if idx.Size > info.Size() {
+		*idx = lineIndex{Offsets: []int64{0}}
+	}
+	if err := idx.extend(io.NewSectionReader(file, idx.Size, info.Size()-idx.Size), newlineOf(encoding)); err != nil {
+		return fmt.Errorf("error while indexing lines of %v with error %v", relativeName(fPath), err)
+	}
+	return nil
+}
+
+// extend is indexing the lines of content written after the indexed size
+// a line feed only counts when it starts at a multiple of its width, the
+// content is read in chunks of whole units so no line feed is split
+func (idx *lineIndex) extend(r io.Reader, newline []byte) error {
+	width := int64(len(newline))
+	buf := make([]byte, 64<<10)
+	for {
+		n, err := io.ReadFull(r, buf)
+		chunk := buf[:n]
+		for from := 0; ; {
+			i := bytes.Index(chunk[from:], newline)
+			if i < 0 {
+				break
+			}
+			start := idx.Size + int64(from+i)
+			if start%width != 0 {
+				from += i + 1
+				continue
+			}
+			from += i + len(newline)
+			idx.Newlines++
+			idx.End = start + width
+			if idx.Newlines%lineIndexInterval == 0 {
+				idx.Offsets = append(idx.Offsets, idx.End)
+			}
+		}
+		idx.Size += int64(n)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package filemanager
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_lineIndex_extend(t *testing.T) {
+	var b strings.Builder
+	for i := 1; i <= 2500; i++ {
+		fmt.Fprintf(&b, "line %v\n", i)
+	}
+	content := b.String() + "partial"
+	// line 1001 starts after 9 lines of 7 bytes, 90 of 8, 900 of 9 and 1 of 10
+	want := &lineIndex{Size: int64(len(content)), Newlines: 2500, End: int64(len(content) - 7), Offsets: []int64{0, 8893, 18893}}
+
+	tests := []struct {
+		name  string
+		parts []string
+	}{
+		{name: "whole content", parts: []string{content}},
+		{name: "appended after a line ending", parts: []string{content[:8893], content[8893:]}},
+		{name: "appended in the middle of a line", parts: []string{content[:100], content[100:18900], content[18900:]}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &lineIndex{Offsets: []int64{0}}
+			for _, part := range tt.parts {
+				if err := got.extend(strings.NewReader(part), newlineOf(encodingUTF8)); err != nil {
+					t.Fatalf("lineIndex.extend() error = %v", err)
+				}
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("lineIndex.extend() = %v, want %v", got, want)
+			}
+			if got.lines() != 2501 {
+				t.Errorf("lineIndex.lines() = %v, want 2501", got.lines())
+			}
+		})
+	}
+}
+
+func Test_lineIndex_extend_utf16(t *testing.T) {
+	// U+0A05 U+0100 U+0A05 is 05 0A 00 01 05 0A in utf-16le and 0A 05 01 00 0A 05
+	// in utf-16be, the line feeds inside are not aligned
+	text := "\ufeffa\u0a05\u0100\u0a05\r\nb\nc"
+	tests := []struct {
+		name     string
+		encoding string
+		want     *lineIndex
+	}{
+		{name: "little endian", encoding: encodingUTF16LE, want: &lineIndex{Size: 20, Newlines: 2, End: 18, Offsets: []int64{0}}},
+		{name: "big endian", encoding: encodingUTF16BE, want: &lineIndex{Size: 20, Newlines: 2, End: 18, Offsets: []int64{0}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := encodeText(text, tt.encoding, false)
+			got := &lineIndex{Offsets: []int64{0}}
+			for _, part := range []string{string(content[:4]), string(content[4:])} {
+				if err := got.extend(strings.NewReader(part), newlineOf(tt.encoding)); err != nil {
+					t.Fatalf("lineIndex.extend() error = %v", err)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("lineIndex.extend() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
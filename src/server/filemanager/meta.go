@@ -0,0 +1,299 @@
+package filemanager
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// metaDir is the directory where the metadata of every file is stored
+// the metadata of "a/b.txt" is "a/b.txt.json"
+var metaDir = "../meta"
+
+// sniffLength is the number of bytes the content type and the text
+// classification are based on
+const sniffLength = 8192
+
+// textExtensions are media types of text files which can not be told
+// apart from plain text by their content
+var textExtensions = map[string]string{
+	".csv":      "text/csv",
+	".tsv":      "text/tab-separated-values",
+	".json":     "application/json",
+	".jsonl":    "application/x-ndjson",
+	".ndjson":   "application/x-ndjson",
+	".md":       "text/markdown",
+	".markdown": "text/markdown",
+	".yaml":     "application/yaml",
+	".yml":      "application/yaml",
+	".log":      "text/plain",
+}
+
+// fileMeta is representing what is known about a stored file
+// ContentType is sniffed from the content (and the extension for text)
+// Text is false for binary files which are skipped by word statistics
+// Encoding, BOM and LineEndings are only detected for text files and
+// OriginalEncoding is the encoding of a file converted to utf-8 on upload
+// Extractor is the extractor of documents (html, markdown, docx, odt)
+// which text is analysed instead of their content
+type fileMeta struct {
+	Name             string `json:"name"`
+	ContentType      string `json:"content_type"`
+	Text             bool   `json:"text"`
+	Size             int64  `json:"size"`
+	Extractor        string `json:"extractor,omitempty"`
+	Encoding         string `json:"encoding,omitempty"`
+	BOM              bool   `json:"bom,omitempty"`
+	LineEndings      string `json:"line_endings,omitempty"`
+	OriginalEncoding string `json:"original_encoding,omitempty"`
+}
+
+// fileInfoRequest is representing the file metadata request
+type fileInfoRequest struct {
+	Name string `json:"name"`
+}
+
+// FileInfo is returning the metadata of a stored file
+func (fm *fileManager) FileInfo(r *http.Request) (interface{}, error) {
+	fileInfoRequest := &fileInfoRequest{}
+	if err := decodeBody(r, fileInfoRequest); err != nil {
+		return nil, fmt.Errorf("file info request body decoding failed with %v", err)
+	}
+
+	filePath, err := getFilePath(fileInfoRequest.Name)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := loadMeta(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, &statusError{code: http.StatusNotFound, err: fmt.Errorf("file does not exist %v", fileInfoRequest.Name)}
+	}
+	return meta, err
+}
+
+// sniffMeta is classifying the content of a file
+// utf-16 text is text even though it has NUL bytes
+func sniffMeta(name string, content []byte) *fileMeta {
+	head := content
+	if len(head) > sniffLength {
+		head = head[:sniffLength]
+	}
+
+	contentType := http.DetectContentType(head)
+	encoding, bomLength := detectEncoding(head, len(head) < len(content))
+	meta := &fileMeta{
+		Name:        name,
+		ContentType: contentType,
+		Text:        isTextContent(head, contentType),
+		Size:        int64(len(content)),
+	}
+	if encoding == encodingUTF16LE || encoding == encodingUTF16BE {
+		meta.Text = true
+	}
+	if !meta.Text {
+		if documentType, ok := documentExtensions[strings.ToLower(path.Ext(name))]; ok && mediaTypeOf(contentType) == "application/zip" {
+			meta.ContentType = documentType
+			meta.Extractor = extractors[documentType].name
+		}
+		return meta
+	}
+
+	mediaType := mediaTypeOf(contentType)
+	if mediaType == "text/plain" || mediaType == "application/octet-stream" {
+		mediaType = "text/plain"
+		if extensionType, ok := textExtensions[strings.ToLower(path.Ext(name))]; ok {
+			mediaType = extensionType
+		}
+	}
+	meta.ContentType = mediaType
+	if strings.HasPrefix(mediaType, "text/") {
+		meta.ContentType += "; charset=" + encoding
+	}
+	if e, ok := extractorFor(mediaType); ok {
+		meta.Extractor = e.name
+	}
+	meta.Encoding = encoding
+	meta.BOM = bomLength > 0
+	if text, err := decodeText(head, encoding); err == nil {
+		meta.LineEndings = detectLineEndings(text)
+	}
+	return meta
+}
+
+// isTextContent is checking whether the beginning of a file is text
+// text has no NUL bytes and few control characters, it does not have to
+// be utf-8 as other encodings are text as well
+func isTextContent(head []byte, contentType string) bool {
+	mediaType := mediaTypeOf(contentType)
+	if strings.HasPrefix(mediaType, "text/") || mediaType == "application/json" || mediaType == "application/xml" {
+		return true
+	}
+	if mediaType != "application/octet-stream" {
+		return false
+	}
+
+	control := 0
+	for i := 0; i < len(head); {
+		r, size := utf8.DecodeRune(head[i:])
+		switch {
+		case r == 0:
+			return false
+		case r < 0x20 && r != '\n' && r != '\r' && r != '\t' && r != '\f' && r != 0x1b:
+			control++
+		}
+		i += size
+	}
+	return control*10 <= len(head)
+}
+
+// mediaTypeOf is returning the content type without parameters
+func mediaTypeOf(contentType string) string {
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		contentType = contentType[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
+
+// metaPath is returning the path of the metadata of a file
+func metaPath(name string) (string, error) {
+	metaPath, err := filepath.Abs(filepath.Join(metaDir, name+".json"))
+	if err != nil {
+		return "", fmt.Errorf("error while creating metadata path %v", err)
+	}
+	return metaPath, nil
+}
+
+// writeMeta is storing the metadata of a file
+func writeMeta(meta *fileMeta) error {
+	metaPath, err := metaPath(meta.Name)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(metaPath), 0755); err != nil {
+		return fmt.Errorf("creating metadata directory failed with %v", err)
+	}
+	if err := ioutil.WriteFile(metaPath, data, 0644); err != nil {
+		return fmt.Errorf("writing metadata of %v failed with %v", meta.Name, err)
+	}
+	return nil
+}
+
+// removeMeta is removing the metadata of a file and the directories left empty
+func removeMeta(name string) error {
+	metaPath, err := metaPath(name)
+	if err != nil {
+		return err
+	}
+	if err := removeSidecar(metaDir, metaPath); err != nil {
+		return fmt.Errorf("removing metadata of %v failed with %v", name, err)
+	}
+	return nil
+}
+
+// removeSidecar is removing a file kept next to a stored file below dir
+// (metadata, line index) and the directories left empty
+func removeSidecar(dir, sidecarPath string) error {
+	if err := os.Remove(sidecarPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	base, err := filepath.Abs(dir)
+	if err != nil {
+		return nil
+	}
+	for dir := filepath.Dir(sidecarPath); strings.HasPrefix(dir, base); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+	return nil
+}
+
+// loadMeta is returning the metadata of a stored file (fPath as given by readDir)
+// files stored without metadata are sniffed
+func loadMeta(fPath string) (*fileMeta, error) {
+	name := relativeName(fPath)
+	metaPath, err := metaPath(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(metaPath)
+	if err == nil {
+		meta := &fileMeta{}
+		if err := json.Unmarshal(data, meta); err == nil {
+			return meta, nil
+		}
+	}
+	return sniffFile(fPath)
+}
+
+// sniffFile is returning the metadata of a stored file from its first bytes
+func sniffFile(fPath string) (*fileMeta, error) {
+	f, err := os.Open(fPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, fmt.Errorf("error while reading file %v with error %v", fPath, err)
+	}
+	meta := sniffMeta(relativeName(fPath), head[:n])
+	meta.Size = info.Size()
+	return meta, nil
+}
+
+// analysable is checking whether the text of a file can be analysed,
+// which is text files and documents with an extractor
+func (m *fileMeta) analysable() bool {
+	_, ok := extractorFor(m.ContentType)
+	return m.Text || ok
+}
+
+// textPaths is returning the paths of the text files and documents
+func textPaths(paths []string) ([]string, error) {
+	texts := make([]string, 0, len(paths))
+	for _, fPath := range paths {
+		meta, err := loadMeta(fPath)
+		if err != nil {
+			return nil, err
+		}
+		if meta.analysable() {
+			texts = append(texts, fPath)
+		}
+	}
+	return texts, nil
+}
+
+// allowedContentType is checking whether a content type matches one of the
+// allowed media types ("text/csv", "text/*" or "*/*")
+func allowedContentType(contentType string, allowed []string) bool {
+	mediaType := mediaTypeOf(contentType)
+	for _, pattern := range allowed {
+		pattern = strings.ToLower(pattern)
+		if pattern == "*/*" || pattern == mediaType {
+			return true
+		}
+		if strings.HasSuffix(pattern, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(pattern, "*")) {
+			return true
+		}
+	}
+	return false
+}
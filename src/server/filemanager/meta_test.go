@@ -0,0 +1,87 @@
+package filemanager
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_sniffMeta(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content []byte
+		want    *fileMeta
+	}{
+		{
+			name:    "plain text",
+			file:    "notes.txt",
+			content: []byte("hello world\n"),
+			want:    &fileMeta{Name: "notes.txt", ContentType: "text/plain; charset=utf-8", Text: true, Size: 12, Encoding: "utf-8", LineEndings: "lf"},
+		},
+		{
+			name:    "csv by extension",
+			file:    "data/people.csv",
+			content: []byte("name,age\nann,31\n"),
+			want:    &fileMeta{Name: "data/people.csv", ContentType: "text/csv; charset=utf-8", Text: true, Size: 16, Encoding: "utf-8", LineEndings: "lf"},
+		},
+		{
+			name:    "png",
+			file:    "logo.txt",
+			content: []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"),
+			want:    &fileMeta{Name: "logo.txt", ContentType: "image/png", Text: false, Size: 16},
+		},
+		{
+			name:    "nul bytes",
+			file:    "blob.bin",
+			content: []byte("ab\x00\x01cd"),
+			want:    &fileMeta{Name: "blob.bin", ContentType: "application/octet-stream", Text: false, Size: 6},
+		},
+		{
+			name:    "few control characters",
+			file:    "term.log",
+			content: []byte("\x1b[1mbold\x1b[0m and a bell\x07 in a longer line of log output\n"),
+			want:    &fileMeta{Name: "term.log", ContentType: "text/plain; charset=utf-8", Text: true, Size: 56, Encoding: "utf-8", LineEndings: "lf"},
+		},
+		{
+			name:    "utf-16 without bom",
+			file:    "wide.txt",
+			content: []byte("h\x00i\x00\r\x00\n\x00"),
+			want:    &fileMeta{Name: "wide.txt", ContentType: "text/plain; charset=utf-16le", Text: true, Size: 8, Encoding: "utf-16le", LineEndings: "crlf"},
+		},
+		{
+			name:    "latin-1",
+			file:    "cafe.txt",
+			content: []byte("caf\xe9\n"),
+			want:    &fileMeta{Name: "cafe.txt", ContentType: "text/plain; charset=iso-8859-1", Text: true, Size: 5, Encoding: "iso-8859-1", LineEndings: "lf"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sniffMeta(tt.file, tt.content); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sniffMeta() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_allowedContentType(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		allowed     []string
+		want        bool
+	}{
+		{name: "exact", contentType: "text/csv; charset=utf-8", allowed: []string{"text/csv"}, want: true},
+		{name: "wildcard subtype", contentType: "text/markdown; charset=utf-8", allowed: []string{"text/*"}, want: true},
+		{name: "any", contentType: "image/png", allowed: []string{"*/*"}, want: true},
+		{name: "other type", contentType: "image/png", allowed: []string{"text/*", "application/json"}, want: false},
+		{name: "case", contentType: "application/json", allowed: []string{"Application/JSON"}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := allowedContentType(tt.contentType, tt.allowed); got != tt.want {
+				t.Errorf("allowedContentType() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package filemanager
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// maxNGramSize is the largest n accepted for n-grams
+const maxNGramSize = 10
+
+// ngramRequest is representing the n-gram frequency request
+// N is the number of words in a n-gram (2 when not given)
+type ngramRequest struct {
+	analysisOptions
+	N      int    `json:"n"`
+	Limit  int    `json:"limit"`
+	Order  string `json:"order"`
+	Offset int    `json:"offset"`
+	Cursor string `json:"cursor"`
+}
+
+// ngramResponse is representing the n-gram frequency response
+type ngramResponse struct {
+	NGrams     []wordFrequency `json:"ngrams"`
+	Total      int             `json:"total"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+// NGrams is counting the n-grams (n adjacent words) of all the files and
+// returning them ranked like WordFrequency
+// n-grams do not cross sentence or file boundaries
+func (fm *fileManager) NGrams(r *http.Request) (interface{}, error) {
+	ngramRequest := &ngramRequest{}
+	if err := decodeBody(r, ngramRequest); err != nil {
+		return nil, fmt.Errorf("ngram request body decoding failed with %v", err)
+	}
+
+	if ngramRequest.N == 0 {
+		ngramRequest.N = 2
+	}
+	if ngramRequest.N < 1 || ngramRequest.N > maxNGramSize {
+		return nil, &statusError{code: http.StatusBadRequest, err: fmt.Errorf("invalid n %v, n has to be between 1 and %v", ngramRequest.N, maxNGramSize)}
+	}
+
+	offset, err := pageOffset(ngramRequest.Offset, ngramRequest.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	analyzer, err := newAnalyzer(ngramRequest.analysisOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	analyses, err := analyzeFiles(analyzer)
+	if err != nil {
+		return nil, err
+	}
+
+	ranked := rankWords(countNGrams(analyses, ngramRequest.N))
+	page, next, err := paginate(ranked, offset, ngramRequest.Limit, ngramRequest.Order)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ngramResponse{
+		NGrams:     page,
+		Total:      len(ranked),
+		NextCursor: next,
+	}, nil
+}
+
+// countNGrams is counting the n-grams of every run of adjacent words
+// words of a n-gram are separated by a single space
+func countNGrams(analyses []*fileAnalysis, n int) map[string]int {
+	ngramCounts := make(map[string]int)
+	words := make([]string, n)
+	for _, analysis := range analyses {
+		tokens := analysis.Tokens
+		for i := 0; i+n <= len(tokens); i++ {
+			if tokens[i].Run != tokens[i+n-1].Run {
+				continue
+			}
+			for j := 0; j < n; j++ {
+				words[j] = tokens[i+j].Word
+			}
+			ngramCounts[strings.Join(words, " ")]++
+		}
+	}
+	return ngramCounts
+}
@@ -0,0 +1,41 @@
+package filemanager
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// foldTable is holding case folds which are not a simple lower casing
+var foldTable = map[rune]string{
+	'ß': "ss",
+	'ẞ': "ss",
+	'ς': "σ",
+}
+
+// composeNFC is composing base letters and combining marks into precomposed
+// letters, unicode normalization form C
+func composeNFC(word string) string {
+	return norm.NFC.String(word)
+}
+
+// composeNFKC is replacing compatibility characters (full width forms,
+// ligatures) with their plain form and composing the result, unicode
+// normalization form KC
+func composeNFKC(word string) string {
+	return norm.NFKC.String(word)
+}
+
+// foldCase is folding the case of a word so that case variants compare equal
+func foldCase(word string) string {
+	var b strings.Builder
+	for _, r := range word {
+		if fold, ok := foldTable[r]; ok {
+			b.WriteString(fold)
+			continue
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
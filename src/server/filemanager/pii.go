@@ -0,0 +1,432 @@
+package filemanager
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// piiConfigPath is the file where the pii rule set and action are stored
+var piiConfigPath = "../config/pii.json"
+
+// pii actions, what happens to uploaded files with pii
+// flag is reporting the findings, reject is refusing the upload and
+// redact is reporting the findings and serving redacted downloads
+const (
+	piiActionFlag   = "flag"
+	piiActionReject = "reject"
+	piiActionRedact = "redact"
+)
+
+// defaultRedaction is replacing pii in redacted downloads
+const defaultRedaction = "[REDACTED]"
+
+// piiRule is a regular expression (RE2) finding pii
+// Luhn is keeping only the matches which pass the Luhn checksum (card numbers)
+type piiRule struct {
+	Name    string `json:"name"`
+	Pattern string `json:"pattern"`
+	Luhn    bool   `json:"luhn"`
+}
+
+// builtinPIIRules are used when the configuration has no rules
+// earlier rules win when matches overlap
+var builtinPIIRules = []piiRule{
+	{Name: "card", Pattern: `\b(?:\d[ -]?){12,18}\d\b`, Luhn: true},
+	{Name: "ssn", Pattern: `\b\d{3}-\d{2}-\d{4}\b`},
+	{Name: "email", Pattern: `(?i)\b[a-z0-9._%+-]+@[a-z0-9-]+(?:\.[a-z0-9-]+)*\.[a-z]{2,}\b`},
+	{Name: "phone", Pattern: `(?:\+\d{1,3}[ .-]?)?(?:\(\d{3}\)|\b\d{3})[ .-]?\d{3}[ .-]?\d{4}\b`},
+}
+
+// piiConfig is representing the pii rule set and the action on uploads
+type piiConfig struct {
+	Action    string    `json:"action"`
+	Redaction string    `json:"redaction"`
+	Rules     []piiRule `json:"rules"`
+}
+
+// piiFinding is representing pii found in a file
+// Line is 1 based, Start and End are byte offsets within the line
+// Text is the match with all but the last four letters and digits masked
+type piiFinding struct {
+	Rule  string `json:"rule"`
+	Line  int    `json:"line"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Text  string `json:"text"`
+}
+
+// piiFileReport is representing the pii found in a file
+// Counts is the number of findings per rule
+type piiFileReport struct {
+	Name     string         `json:"name"`
+	Findings []piiFinding   `json:"findings"`
+	Counts   map[string]int `json:"counts"`
+}
+
+// piiReportRequest is representing the pii report request
+// binary files are only scanned with IncludeBinary
+type piiReportRequest struct {
+	fileFilter
+	IncludeBinary bool `json:"include_binary"`
+}
+
+// piiReportResponse is representing the files with pii
+// Scanned is the number of files which were scanned
+type piiReportResponse struct {
+	Scanned int             `json:"scanned"`
+	Files   []piiFileReport `json:"files"`
+}
+
+// piiMatcher is a compiled pii rule
+type piiMatcher struct {
+	name    string
+	pattern *regexp.Regexp
+	luhn    bool
+}
+
+// piiScanner is finding and redacting pii with a rule set
+type piiScanner struct {
+	matchers  []piiMatcher
+	redaction string
+}
+
+// piiMatch is a match of a rule in a line
+type piiMatch struct {
+	rule  string
+	start int
+	end   int
+}
+
+// PIIConfig is returning the pii rule set and action in use
+func (fm *fileManager) PIIConfig(_ *http.Request) (interface{}, error) {
+	return loadPIIConfig()
+}
+
+// UpdatePIIConfig is replacing the pii rule set and action
+// a configuration without rules is using the built in rules, which are not
+// stored so that the configuration keeps up with them
+func (fm *fileManager) UpdatePIIConfig(r *http.Request) (interface{}, error) {
+	config := &piiConfig{}
+	if err := decodeBody(r, config); err != nil {
+		return nil, fmt.Errorf("pii config request body decoding failed with %v", err)
+	}
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	config.withDefaults()
+	if _, err := newPIIScanner(config); err != nil {
+		return nil, &statusError{code: http.StatusBadRequest, err: err}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(piiConfigPath), 0755); err != nil {
+		return nil, fmt.Errorf("creating config directory failed with %v", err)
+	}
+	if err := ioutil.WriteFile(piiConfigPath, data, 0644); err != nil {
+		return nil, fmt.Errorf("writing pii config failed with %v", err)
+	}
+	return config, nil
+}
+
+// PIIReport is scanning the selected files and returning the pii of every file
+func (fm *fileManager) PIIReport(r *http.Request) (interface{}, error) {
+	piiReportRequest := &piiReportRequest{}
+	if err := decodeBody(r, piiReportRequest); err != nil {
+		return nil, fmt.Errorf("pii report request body decoding failed with %v", err)
+	}
+
+	config, err := loadPIIConfig()
+	if err != nil {
+		return nil, err
+	}
+	scanner, err := newPIIScanner(config)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := readDir(filesDir)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading files for pii report %v", err)
+	}
+	files, err = piiReportRequest.selectPaths(files)
+	if err != nil {
+		return nil, err
+	}
+	if !piiReportRequest.IncludeBinary {
+		if files, err = textPaths(files); err != nil {
+			return nil, err
+		}
+	}
+
+	piiReportResponse := &piiReportResponse{
+		Scanned: len(files),
+		Files:   []piiFileReport{},
+	}
+	for _, fPath := range files {
+		content, err := readText(fPath)
+		if err != nil {
+			return nil, fmt.Errorf("error while reading file %v with error %v", fPath, err)
+		}
+		if report := scanner.report(relativeName(fPath), content); report != nil {
+			piiReportResponse.Files = append(piiReportResponse.Files, *report)
+		}
+	}
+	return piiReportResponse, nil
+}
+
+// checkPII is scanning uploaded files with the configured rules
+// with the reject action files with pii are refused (422) and none is stored
+// otherwise the findings are logged and returned
+func checkPII(files []file) ([]piiFileReport, error) {
+	config, err := loadPIIConfig()
+	if err != nil {
+		return nil, err
+	}
+	scanner, err := newPIIScanner(config)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := []piiFileReport{}
+	for _, file := range files {
+		if report := scanner.report(file.Name, uploadedText(file)); report != nil {
+			reports = append(reports, *report)
+		}
+	}
+	if len(reports) == 0 {
+		return nil, nil
+	}
+
+	names := make([]string, 0, len(reports))
+	for _, report := range reports {
+		names = append(names, fmt.Sprintf("%v (%v)", report.Name, report.countsText()))
+	}
+	if config.Action == piiActionReject {
+		return nil, &statusError{
+			code: http.StatusUnprocessableEntity,
+			err:  fmt.Errorf("files rejected, pii found in %v", strings.Join(names, ", ")),
+		}
+	}
+	log.Printf("pii found in %v", strings.Join(names, ", "))
+	return reports, nil
+}
+
+// countsText is the number of findings per rule as text (1 email, 2 phone)
+func (report *piiFileReport) countsText() string {
+	counts := make([]string, 0, len(report.Counts))
+	for rule, count := range report.Counts {
+		counts = append(counts, fmt.Sprintf("%v %v", count, rule))
+	}
+	sort.Strings(counts)
+	return strings.Join(counts, ", ")
+}
+
+// loadPIIConfig is reading the pii configuration, the built in rules with
+// the flag action are used when nothing is configured
+func loadPIIConfig() (*piiConfig, error) {
+	config := &piiConfig{}
+	data, err := ioutil.ReadFile(piiConfigPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("reading pii config failed with %v", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, config); err != nil {
+			return nil, fmt.Errorf("pii config %v is invalid with %v", piiConfigPath, err)
+		}
+	}
+	config.withDefaults()
+	return config, nil
+}
+
+// withDefaults is filling the action, redaction and rules which are not given
+func (c *piiConfig) withDefaults() {
+	if c.Action == "" {
+		c.Action = piiActionFlag
+	}
+	if c.Redaction == "" {
+		c.Redaction = defaultRedaction
+	}
+	if len(c.Rules) == 0 {
+		c.Rules = builtinPIIRules
+	}
+}
+
+// newPIIScanner is compiling the rules of the configuration
+func newPIIScanner(c *piiConfig) (*piiScanner, error) {
+	switch c.Action {
+	case piiActionFlag, piiActionReject, piiActionRedact:
+	default:
+		return nil, fmt.Errorf("invalid pii action %v, action has to be %v, %v or %v", c.Action, piiActionFlag, piiActionReject, piiActionRedact)
+	}
+
+	scanner := &piiScanner{redaction: c.Redaction}
+	for _, rule := range c.Rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("pii rule %v has no name", rule.Pattern)
+		}
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern of pii rule %v with error %v", rule.Name, err)
+		}
+		scanner.matchers = append(scanner.matchers, piiMatcher{name: rule.Name, pattern: pattern, luhn: rule.Luhn})
+	}
+	return scanner, nil
+}
+
+// matchLine is returning the pii of a line in the order it appears
+// when matches overlap the match of the earlier rule is kept
+func (ps *piiScanner) matchLine(line string) []piiMatch {
+	matches := []piiMatch{}
+	taken := func(start, end int) bool {
+		for _, m := range matches {
+			if start < m.end && m.start < end {
+				return true
+			}
+		}
+		return false
+	}
+	for _, matcher := range ps.matchers {
+		for _, loc := range matcher.pattern.FindAllStringIndex(line, -1) {
+			if loc[0] == loc[1] || taken(loc[0], loc[1]) {
+				continue
+			}
+			if matcher.luhn && !luhnValid(line[loc[0]:loc[1]]) {
+				continue
+			}
+			matches = append(matches, piiMatch{rule: matcher.name, start: loc[0], end: loc[1]})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].start < matches[j].start
+	})
+	return matches
+}
+
+// report is returning the pii found in the content, nil when there is none
+func (ps *piiScanner) report(name string, content []byte) *piiFileReport {
+	report := &piiFileReport{
+		Name:     name,
+		Findings: []piiFinding{},
+		Counts:   make(map[string]int),
+	}
+	eachLine(content, func(lineNumber int, line string) {
+		for _, m := range ps.matchLine(line) {
+			report.Findings = append(report.Findings, piiFinding{
+				Rule:  m.rule,
+				Line:  lineNumber,
+				Start: m.start,
+				End:   m.end,
+				Text:  maskPII(line[m.start:m.end]),
+			})
+			report.Counts[m.rule]++
+		}
+	})
+	if len(report.Findings) == 0 {
+		return nil
+	}
+	return report
+}
+
+// redact is returning the content with every pii replaced
+func (ps *piiScanner) redact(content []byte) []byte {
+	var redacted bytes.Buffer
+	redacted.Grow(len(content))
+	eachLine(content, func(_ int, line string) {
+		from := 0
+		for _, m := range ps.matchLine(line) {
+			redacted.WriteString(line[from:m.start])
+			redacted.WriteString(ps.redaction)
+			from = m.end
+		}
+		redacted.WriteString(line[from:])
+	})
+	return redacted.Bytes()
+}
+
+// redactingScanner is returning the pii scanner when the pii action is
+// redact so that what is read from the files is redacted, it is nil when
+// pii is not redacted
+func redactingScanner() (*piiScanner, error) {
+	config, err := loadPIIConfig()
+	if err != nil {
+		return nil, err
+	}
+	if config.Action != piiActionRedact {
+		return nil, nil
+	}
+	return newPIIScanner(config)
+}
+
+// redactText is returning text with every pii replaced, a nil scanner is
+// returning it unchanged
+func (ps *piiScanner) redactText(text string) string {
+	if ps == nil {
+		return text
+	}
+	return string(ps.redact([]byte(text)))
+}
+
+// eachLine is calling fn with every line (including its line ending) of the content
+func eachLine(content []byte, fn func(lineNumber int, line string)) {
+	rdr := bufio.NewReader(bytes.NewReader(content))
+	for lineNumber := 1; ; lineNumber++ {
+		line, err := rdr.ReadString('\n')
+		if line != "" {
+			fn(lineNumber, line)
+		}
+		if err == io.EOF {
+			return
+		}
+	}
+}
+
+// luhnValid is checking the Luhn checksum of the digits of a card like number
+func luhnValid(number string) bool {
+	sum, digits := 0, 0
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if digits%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		digits++
+	}
+	return digits >= 13 && digits <= 19 && sum%10 == 0
+}
+
+// maskPII is masking all but the last four letters and digits of a match
+func maskPII(text string) string {
+	runes := []rune(text)
+	visible := 0
+	for i := len(runes) - 1; i >= 0; i-- {
+		if !unicode.IsLetter(runes[i]) && !unicode.IsDigit(runes[i]) {
+			continue
+		}
+		if visible < 4 {
+			visible++
+			continue
+		}
+		runes[i] = '*'
+	}
+	return string(runes)
+}
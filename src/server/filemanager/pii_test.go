@@ -0,0 +1,60 @@
+package filemanager
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_luhnValid(t *testing.T) {
+	tests := []struct {
+		number string
+		want   bool
+	}{
+		{number: "4111 1111 1111 1111", want: true},
+		{number: "4111-1111-1111-1112", want: false},
+		{number: "378282246310005", want: true},
+		{number: "0000000000", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.number, func(t *testing.T) {
+			if got := luhnValid(tt.number); got != tt.want {
+				t.Errorf("luhnValid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_piiScanner(t *testing.T) {
+	config := &piiConfig{}
+	config.withDefaults()
+	scanner, err := newPIIScanner(config)
+	if err != nil {
+		t.Fatalf("newPIIScanner() error = %v", err)
+	}
+	content := []byte("card 4111 1111 1111 1111 not 4111 1111 1111 1112\nmail ann@mail.org or call (555) 123-4567\n")
+
+	want := &piiFileReport{
+		Name: "a.txt",
+		Findings: []piiFinding{
+			{Rule: "card", Line: 1, Start: 5, End: 24, Text: "**** **** **** 1111"},
+			{Rule: "email", Line: 2, Start: 5, End: 17, Text: "***@***l.org"},
+			{Rule: "phone", Line: 2, Start: 26, End: 40, Text: "(***) ***-4567"},
+		},
+		Counts: map[string]int{"card": 1, "email": 1, "phone": 1},
+	}
+	if got := scanner.report("a.txt", content); !reflect.DeepEqual(got, want) {
+		t.Errorf("piiScanner.report() = %v, want %v", got, want)
+	}
+	if got := scanner.report("b.txt", []byte("hello world")); got != nil {
+		t.Errorf("piiScanner.report() = %v, want nil", got)
+	}
+
+	wantRedacted := "card [REDACTED] not 4111 1111 1111 1112\nmail [REDACTED] or call [REDACTED]\n"
+	if got := string(scanner.redact(content)); got != wantRedacted {
+		t.Errorf("piiScanner.redact() = %q, want %q", got, wantRedacted)
+	}
+
+	if _, err := newPIIScanner(&piiConfig{Action: "drop"}); err == nil {
+		t.Errorf("newPIIScanner() with invalid action, want error")
+	}
+}
@@ -0,0 +1,171 @@
+package filemanager
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// policiesPath is the file where the upload policies are stored
+var policiesPath = "../config/policies.json"
+
+// secret policy actions, what happens to uploaded files with secrets
+// warn is storing the file and reporting the secrets, reject is refusing
+// the upload (422) and quarantine is storing the file in the quarantine
+const (
+	secretActionWarn       = "warn"
+	secretActionReject     = "reject"
+	secretActionQuarantine = "quarantine"
+)
+
+// namespacePolicy is representing the upload policy of a namespace
+// MIMETypes are the allowed content types ("text/csv", "text/*"), any
+// content type is allowed when there are none
+// empty fields are taken from the default policy
+type namespacePolicy struct {
+	Secrets   string   `json:"secrets,omitempty"`
+	MIMETypes []string `json:"mime_types,omitempty"`
+}
+
+// policyConfig is representing the upload policies
+// a namespace is the first directory of a file name ("team/notes.txt" is
+// in the team namespace), files at the top are in the "" namespace
+type policyConfig struct {
+	Default    namespacePolicy            `json:"default"`
+	Namespaces map[string]namespacePolicy `json:"namespaces"`
+}
+
+// Policies is returning the upload policies in use
+func (fm *fileManager) Policies(_ *http.Request) (interface{}, error) {
+	return loadPolicies()
+}
+
+// UpdatePolicies is replacing the upload policies
+func (fm *fileManager) UpdatePolicies(r *http.Request) (interface{}, error) {
+	config := &policyConfig{}
+	if err := decodeBody(r, config); err != nil {
+		return nil, fmt.Errorf("policies request body decoding failed with %v", err)
+	}
+	if err := config.validate(); err != nil {
+		return nil, &statusError{code: http.StatusBadRequest, err: err}
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(policiesPath), 0755); err != nil {
+		return nil, fmt.Errorf("creating config directory failed with %v", err)
+	}
+	if err := ioutil.WriteFile(policiesPath, data, 0644); err != nil {
+		return nil, fmt.Errorf("writing policies failed with %v", err)
+	}
+	config.withDefaults()
+	return config, nil
+}
+
+// loadPolicies is reading the upload policies, secrets are only reported
+// (warn) when nothing is configured
+func loadPolicies() (*policyConfig, error) {
+	config := &policyConfig{}
+	data, err := ioutil.ReadFile(policiesPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("reading policies failed with %v", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, config); err != nil {
+			return nil, fmt.Errorf("policies %v are invalid with %v", policiesPath, err)
+		}
+	}
+	config.withDefaults()
+	return config, nil
+}
+
+// withDefaults is filling the default policy fields which are not given
+func (c *policyConfig) withDefaults() {
+	if c.Default.Secrets == "" {
+		c.Default.Secrets = secretActionWarn
+	}
+	if c.Namespaces == nil {
+		c.Namespaces = make(map[string]namespacePolicy)
+	}
+}
+
+// validate is checking the actions of all the policies
+func (c *policyConfig) validate() error {
+	policies := map[string]namespacePolicy{"default": c.Default}
+	for namespace, policy := range c.Namespaces {
+		policies["namespace "+namespace] = policy
+	}
+	for name, policy := range policies {
+		switch policy.Secrets {
+		case "", secretActionWarn, secretActionReject, secretActionQuarantine:
+		default:
+			return fmt.Errorf("invalid secrets action %v of %v, action has to be %v, %v or %v",
+				policy.Secrets, name, secretActionWarn, secretActionReject, secretActionQuarantine)
+		}
+		for _, mimeType := range policy.MIMETypes {
+			if parts := strings.Split(mimeType, "/"); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+				return fmt.Errorf("invalid mime type %v of %v", mimeType, name)
+			}
+		}
+	}
+	return nil
+}
+
+// checkContentTypes is sniffing the content type of uploaded files before
+// they are written, when a file is not allowed in its namespace (415) none
+// of the files is stored
+func checkContentTypes(files []file) (map[string]*fileMeta, error) {
+	policies, err := loadPolicies()
+	if err != nil {
+		return nil, err
+	}
+
+	metas := make(map[string]*fileMeta, len(files))
+	for _, file := range files {
+		meta := sniffMeta(file.Name, file.Content)
+		allowed := policies.policyFor(file.Name).MIMETypes
+		if len(allowed) > 0 && !allowedContentType(meta.ContentType, allowed) {
+			return nil, &statusError{
+				code: http.StatusUnsupportedMediaType,
+				err: fmt.Errorf("file %v of type %v is not allowed in namespace %q, allowed types are %v",
+					file.Name, mediaTypeOf(meta.ContentType), namespaceOf(file.Name), strings.Join(allowed, ", ")),
+			}
+		}
+		metas[file.Name] = meta
+	}
+	return metas, nil
+}
+
+// policyFor is returning the policy of the namespace of a file
+func (c *policyConfig) policyFor(name string) namespacePolicy {
+	policy := c.Default
+	namespace, ok := c.Namespaces[namespaceOf(name)]
+	if !ok {
+		return policy
+	}
+	if namespace.Secrets != "" {
+		policy.Secrets = namespace.Secrets
+	}
+	if len(namespace.MIMETypes) > 0 {
+		policy.MIMETypes = namespace.MIMETypes
+	}
+	return policy
+}
+
+// namespaceOf is returning the namespace (first directory) of a file name
+// the name is cleaned first as it is stored cleaned ("a/../team/x.txt" is in team)
+func namespaceOf(name string) string {
+	name = strings.TrimPrefix(path.Clean("/"+filepath.ToSlash(name)), "/")
+	if i := strings.Index(name, "/"); i >= 0 {
+		return name[:i]
+	}
+	return ""
+}
@@ -0,0 +1,226 @@
+package filemanager
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// jsonContentTypes are the media types of the files which can be queried,
+// every line of an ndjson file is a document
+var jsonContentTypes = map[string]bool{
+	"application/json":     true,
+	"application/x-ndjson": true,
+}
+
+// queryRequest is representing the JSONPath query request
+// Limit is the largest number of streamed matches, 0 is no limit
+type queryRequest struct {
+	fileFilter
+	Query string `json:"query"`
+	Limit int    `json:"limit"`
+}
+
+// queryRecord is representing a streamed match or a document which is not json
+// Type is "match" or "error", Line is the line of an ndjson document
+type queryRecord struct {
+	Type  string      `json:"type"`
+	File  string      `json:"file"`
+	Line  int         `json:"line,omitempty"`
+	Path  string      `json:"path,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+	Error string      `json:"error,omitempty"`
+}
+
+// querySummary is representing the last streamed record of a query
+// Truncated is set when the limit stopped the query
+type querySummary struct {
+	Type      string `json:"type"`
+	Matches   int    `json:"matches"`
+	Files     int    `json:"files"`
+	Truncated bool   `json:"truncated,omitempty"`
+}
+
+// querier is evaluating a query against documents and streaming the matches
+type querier struct {
+	path    jsonPath
+	scanner *piiScanner
+	request *queryRequest
+	encoder *json.Encoder
+	flush   func()
+	matches int
+	files   int
+}
+
+// Query is evaluating a JSONPath query against the selected json and ndjson
+// files and streaming every selected value as ndjson
+// pii in the selected values is redacted when the pii action is redact
+func (fm *fileManager) Query(w http.ResponseWriter, r *http.Request) error {
+	queryRequest := &queryRequest{}
+	if err := decodeBody(r, queryRequest); err != nil {
+		return fmt.Errorf("query request body decoding failed with %v", err)
+	}
+	path, err := parseJSONPath(queryRequest.Query)
+	if err != nil {
+		return &statusError{code: http.StatusBadRequest, err: err}
+	}
+
+	scanner, err := redactingScanner()
+	if err != nil {
+		return err
+	}
+	files, err := readDir(filesDir)
+	if err != nil {
+		return fmt.Errorf("error while reading files for query %v", err)
+	}
+	files, err = queryRequest.selectPaths(files)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	q := &querier{
+		path:    path,
+		scanner: scanner,
+		request: queryRequest,
+		encoder: json.NewEncoder(w),
+		flush:   func() {},
+	}
+	if flusher, ok := w.(http.Flusher); ok {
+		q.flush = flusher.Flush
+	}
+
+	for _, fPath := range files {
+		if err := r.Context().Err(); err != nil {
+			return err
+		}
+		meta, err := loadMeta(fPath)
+		if err != nil {
+			return err
+		}
+		if !jsonContentTypes[mediaTypeOf(meta.ContentType)] {
+			continue
+		}
+		if err := q.queryFile(fPath, mediaTypeOf(meta.ContentType) == "application/x-ndjson"); err != nil {
+			return err
+		}
+		if q.done() {
+			break
+		}
+	}
+	return q.encoder.Encode(&querySummary{Type: "summary", Matches: q.matches, Files: q.files, Truncated: q.done()})
+}
+
+// done is checking whether the limit of matches is reached
+func (q *querier) done() bool {
+	return q.request.Limit > 0 && q.matches >= q.request.Limit
+}
+
+// queryFile is streaming the matches of the documents of a file, a json
+// file may have several documents one after another
+func (q *querier) queryFile(fPath string, lines bool) error {
+	file, err := openText(fPath)
+	if err != nil {
+		return fmt.Errorf("error while opening file %v with error %v", fPath, err)
+	}
+	defer file.Close()
+
+	name := relativeName(fPath)
+	before := q.matches
+	if lines {
+		rdr := bufio.NewReader(file)
+		for lineNumber := 1; !q.done(); lineNumber++ {
+			line, err := rdr.ReadString('\n')
+			if strings.TrimSpace(line) != "" {
+				if err := q.queryDocuments(name, lineNumber, strings.NewReader(line)); err != nil {
+					return err
+				}
+			}
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("error while reading file %v with error %v", fPath, err)
+			}
+		}
+	} else if err := q.queryDocuments(name, 0, file); err != nil {
+		return err
+	}
+
+	if q.matches > before {
+		q.files++
+	}
+	q.flush()
+	return nil
+}
+
+// queryDocuments is streaming the matches of the documents of r, a
+// document which is not json is streamed as an error record
+func (q *querier) queryDocuments(name string, line int, r io.Reader) error {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	for !q.done() {
+		var document interface{}
+		if err := decoder.Decode(&document); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return q.encoder.Encode(&queryRecord{Type: "error", File: name, Line: line, Error: err.Error()})
+		}
+		for _, node := range q.path.eval(document) {
+			if err := q.encoder.Encode(&queryRecord{Type: "match", File: name, Line: line, Path: node.path, Value: jsonValue{redactValue(q.scanner, node.value)}}); err != nil {
+				return err
+			}
+			q.matches++
+			if q.done() {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// redactValue is returning a selected value with the pii of its strings
+// replaced, a nil scanner is returning it unchanged
+func redactValue(scanner *piiScanner, value interface{}) interface{} {
+	if scanner == nil {
+		return value
+	}
+	switch v := value.(type) {
+	case string:
+		return scanner.redactText(v)
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = redactValue(scanner, item)
+		}
+		return redacted
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			redacted[key] = redactValue(scanner, item)
+		}
+		return redacted
+	}
+	return value
+}
+
+// jsonValue is a selected value which is encoded even when it is null
+type jsonValue struct {
+	value interface{}
+}
+
+// MarshalJSON is encoding the value without html escaping
+func (v jsonValue) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v.value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(b.Bytes(), "\n"), nil
+}
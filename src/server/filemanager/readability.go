@@ -0,0 +1,242 @@
+package filemanager
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// sentenceBucketSize is the number of sentence lengths in a bucket of the distribution
+const sentenceBucketSize = 5
+
+// readabilityRequest is representing the readability request
+// stop words and stemming are not used, readability counts every word as written
+type readabilityRequest struct {
+	analysisOptions
+}
+
+// lengthBucket is representing the number of sentences with From to To words
+type lengthBucket struct {
+	From  int `json:"from"`
+	To    int `json:"to"`
+	Count int `json:"count"`
+}
+
+// sentenceLengths is representing the distribution of the sentence lengths in words
+type sentenceLengths struct {
+	Min     int            `json:"min"`
+	Max     int            `json:"max"`
+	Mean    float64        `json:"mean"`
+	Median  float64        `json:"median"`
+	Buckets []lengthBucket `json:"buckets"`
+}
+
+// readabilityStats is representing the sentence level statistics and the
+// readability scores, complex words are words with three or more syllables
+type readabilityStats struct {
+	Sentences          int             `json:"sentences"`
+	Words              int             `json:"words"`
+	UniqueWords        int             `json:"unique_words"`
+	Syllables          int             `json:"syllables"`
+	ComplexWords       int             `json:"complex_words"`
+	FleschReadingEase  float64         `json:"flesch_reading_ease"`
+	FleschKincaidGrade float64         `json:"flesch_kincaid_grade"`
+	GunningFog         float64         `json:"gunning_fog"`
+	TypeTokenRatio     float64         `json:"type_token_ratio"`
+	SentenceLengths    sentenceLengths `json:"sentence_lengths"`
+}
+
+// fileReadability is representing the readability of a single file
+type fileReadability struct {
+	Name string `json:"name"`
+	readabilityStats
+}
+
+// readabilityResponse is representing the readability per file and for all of them
+type readabilityResponse struct {
+	Files []fileReadability `json:"files"`
+	Total readabilityStats  `json:"total"`
+}
+
+// readabilityCounts is what the readability scores are computed from
+type readabilityCounts struct {
+	syllables int
+	complex   int
+	counts    map[string]int
+	lengths   []int
+}
+
+// Analyze is returning sentence statistics, readability scores and lexical
+// diversity of every file and of all of them together
+func (fm *fileManager) Analyze(r *http.Request) (interface{}, error) {
+	readabilityRequest := &readabilityRequest{}
+	if err := decodeBody(r, readabilityRequest); err != nil {
+		return nil, fmt.Errorf("analyze request body decoding failed with %v", err)
+	}
+	readabilityRequest.StopWords = nil
+	readabilityRequest.Stem = false
+
+	analyzer, err := newAnalyzer(readabilityRequest.analysisOptions)
+	if err != nil {
+		return nil, err
+	}
+	analyses, err := analyzeFiles(analyzer)
+	if err != nil {
+		return nil, err
+	}
+
+	readabilityResponse := &readabilityResponse{
+		Files: make([]fileReadability, 0, len(analyses)),
+	}
+	total := &readabilityCounts{counts: make(map[string]int)}
+	for _, analysis := range analyses {
+		counts := countReadability(analysis)
+		readabilityResponse.Files = append(readabilityResponse.Files, fileReadability{
+			Name:             analysis.Name,
+			readabilityStats: counts.stats(),
+		})
+
+		total.syllables += counts.syllables
+		total.complex += counts.complex
+		total.lengths = append(total.lengths, counts.lengths...)
+		for word, count := range counts.counts {
+			total.counts[word] += count
+		}
+	}
+	readabilityResponse.Total = total.stats()
+	return readabilityResponse, nil
+}
+
+// countReadability is counting the syllables, complex words and sentence lengths of a file
+func countReadability(analysis *fileAnalysis) *readabilityCounts {
+	counts := &readabilityCounts{
+		counts:  analysis.Counts,
+		lengths: make([]int, analysis.sentenceCount()),
+	}
+	for _, token := range analysis.Tokens {
+		counts.lengths[token.Sentence]++
+		counts.syllables += syllables(token.Word)
+		if isComplexWord(token.Word) {
+			counts.complex++
+		}
+	}
+	return counts
+}
+
+// stats is returning the readability scores of the counts
+func (rc *readabilityCounts) stats() readabilityStats {
+	stats := readabilityStats{
+		Sentences:       len(rc.lengths),
+		UniqueWords:     len(rc.counts),
+		Syllables:       rc.syllables,
+		ComplexWords:    rc.complex,
+		SentenceLengths: distribution(rc.lengths),
+	}
+	for _, length := range rc.lengths {
+		stats.Words += length
+	}
+	if stats.Words == 0 {
+		return stats
+	}
+
+	words := float64(stats.Words)
+	wordsPerSentence := words / float64(stats.Sentences)
+	syllablesPerWord := float64(stats.Syllables) / words
+	stats.FleschReadingEase = 206.835 - 1.015*wordsPerSentence - 84.6*syllablesPerWord
+	stats.FleschKincaidGrade = 0.39*wordsPerSentence + 11.8*syllablesPerWord - 15.59
+	stats.GunningFog = 0.4 * (wordsPerSentence + 100*float64(stats.ComplexWords)/words)
+	stats.TypeTokenRatio = float64(stats.UniqueWords) / words
+	return stats
+}
+
+// distribution is returning the distribution of the sentence lengths
+func distribution(lengths []int) sentenceLengths {
+	dist := sentenceLengths{Buckets: []lengthBucket{}}
+	if len(lengths) == 0 {
+		return dist
+	}
+
+	sorted := append([]int(nil), lengths...)
+	sort.Ints(sorted)
+	dist.Min, dist.Max = sorted[0], sorted[len(sorted)-1]
+
+	sum := 0
+	for _, length := range sorted {
+		sum += length
+		bucket := (length - 1) / sentenceBucketSize
+		for len(dist.Buckets) <= bucket {
+			from := len(dist.Buckets)*sentenceBucketSize + 1
+			dist.Buckets = append(dist.Buckets, lengthBucket{From: from, To: from + sentenceBucketSize - 1})
+		}
+		dist.Buckets[bucket].Count++
+	}
+	dist.Mean = float64(sum) / float64(len(sorted))
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		dist.Median = float64(sorted[middle])
+	} else {
+		dist.Median = float64(sorted[middle-1]+sorted[middle]) / 2
+	}
+	return dist
+}
+
+// syllables is estimating the number of syllables of an english word
+// by counting groups of vowels, a silent e and the -es and -ed endings
+// which are not pronounced as a syllable are not counted
+func syllables(word string) int {
+	word = strings.ToLower(word)
+	letters := []rune{}
+	for _, r := range word {
+		if unicode.IsLetter(r) {
+			letters = append(letters, r)
+		}
+	}
+
+	count := 0
+	previousVowel := false
+	for i, r := range letters {
+		vowel := isVowel(r) || (r == 'y' && i > 0)
+		if vowel && !previousVowel {
+			count++
+		}
+		previousVowel = vowel
+	}
+
+	n := len(letters)
+	if count > 1 && n > 2 {
+		last, beforeLast, third := letters[n-1], letters[n-2], letters[n-3]
+		switch {
+		case last == 'e' && !(beforeLast == 'l' && !isVowel(third)):
+			count--
+		case last == 's' && beforeLast == 'e' && !strings.ContainsRune("sxzcgh", third):
+			count--
+		case last == 'd' && beforeLast == 'e' && !strings.ContainsRune("td", third):
+			count--
+		}
+	}
+
+	if count == 0 {
+		return 1
+	}
+	return count
+}
+
+// isVowel is checking whether a lower case letter is a vowel (y is not)
+func isVowel(r rune) bool {
+	return strings.ContainsRune("aeiouàáâäèéêëìíîïòóôöùúûü", r)
+}
+
+// isComplexWord is checking whether a word has three or more syllables
+// not counting the -es, -ed and -ing endings as Gunning fog does
+func isComplexWord(word string) bool {
+	for _, suffix := range []string{"ing", "es", "ed"} {
+		if strings.HasSuffix(word, suffix) && len(word) > len(suffix)+2 {
+			word = strings.TrimSuffix(word, suffix)
+			break
+		}
+	}
+	return syllables(word) >= 3
+}
@@ -0,0 +1,58 @@
+package filemanager
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_syllables(t *testing.T) {
+	tests := []struct {
+		word string
+		want int
+	}{
+		{word: "the", want: 1},
+		{word: "cat", want: 1},
+		{word: "make", want: 1},
+		{word: "table", want: 2},
+		{word: "jumped", want: 1},
+		{word: "wanted", want: 2},
+		{word: "makes", want: 1},
+		{word: "boxes", want: 2},
+		{word: "yes", want: 1},
+		{word: "rhythm", want: 1},
+		{word: "beautiful", want: 3},
+		{word: "readability", want: 5},
+		{word: "2024", want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			if got := syllables(tt.word); got != tt.want {
+				t.Errorf("syllables() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_countReadability(t *testing.T) {
+	a, _ := newAnalyzer(analysisOptions{})
+	analysis, _ := analyzeReader("", strings.NewReader("The cat sat. It was a really beautiful\nday!\n\nReadability matters"), a)
+	got := countReadability(analysis).stats()
+
+	if got.Sentences != 3 || got.Words != 11 || got.Syllables != 19 || got.ComplexWords != 2 || got.UniqueWords != 11 {
+		t.Errorf("countReadability() = %+v", got)
+	}
+	want := sentenceLengths{
+		Min:     2,
+		Max:     6,
+		Mean:    11.0 / 3,
+		Median:  3,
+		Buckets: []lengthBucket{{From: 1, To: 5, Count: 2}, {From: 6, To: 10, Count: 1}},
+	}
+	if !reflect.DeepEqual(got.SentenceLengths, want) {
+		t.Errorf("countReadability() lengths = %+v, want %+v", got.SentenceLengths, want)
+	}
+	if got.TypeTokenRatio != 1 || got.FleschReadingEase >= 206.835 {
+		t.Errorf("countReadability() scores = %+v", got)
+	}
+}
@@ -2,6 +2,7 @@ package filemanager
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -21,6 +22,35 @@ func (hf HandlerFunc) ServeHTTP(r *http.Request) (interface{}, error) {
 	return hf(r)
 }
 
+// statusError is an error answered with its own status code and a json
+// body with the message, other errors are internal server errors
+type statusError struct {
+	code int
+	err  error
+}
+
+func (se *statusError) Error() string {
+	return se.err.Error()
+}
+
+// errorResponse is representing the body of a status error response
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// writeError is writing the status code of the error
+func writeError(w http.ResponseWriter, err error) {
+	var se *statusError
+	if !errors.As(err, &se) {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	data, _ := json.Marshal(&errorResponse{Error: se.Error()})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(se.code)
+	w.Write(data)
+}
+
 // controller is a wrapper holding custom Handler
 type controller struct {
 	handler Handler
@@ -31,7 +61,7 @@ func (ctr controller) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	res, err := ctr.handler.ServeHTTP(r)
 	if err != nil {
 		fmt.Printf("error occured from serveHTTP : %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
 
@@ -51,6 +81,51 @@ func (ctr controller) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+// StreamHandlerFunc is a handler writing the response itself
+// used for streamed responses (ndjson) which can not be marshaled at once
+type StreamHandlerFunc func(http.ResponseWriter, *http.Request) error
+
+// streamController is a wrapper holding a StreamHandlerFunc
+type streamController struct {
+	handler StreamHandlerFunc
+}
+
+// streamWriter is remembering whether the response was started
+type streamWriter struct {
+	http.ResponseWriter
+	started bool
+}
+
+func (sw *streamWriter) WriteHeader(statusCode int) {
+	sw.started = true
+	sw.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (sw *streamWriter) Write(data []byte) (int, error) {
+	sw.started = true
+	return sw.ResponseWriter.Write(data)
+}
+
+// Flush is sending the buffered data to the client
+func (sw *streamWriter) Flush() {
+	if flusher, ok := sw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// ServeHTTP over streamController making it acts http.Handler
+// an error before anything was written is answered like in controller,
+// afterwards the response is just ended
+func (ctr streamController) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	sw := &streamWriter{ResponseWriter: w}
+	if err := ctr.handler(sw, r); err != nil {
+		fmt.Printf("error occured from stream : %v", err)
+		if !sw.started {
+			writeError(w, err)
+		}
+	}
+}
+
 // Router is wrapping *mux.Router
 type Router struct {
 	RouteHandler *mux.Router
@@ -70,3 +145,8 @@ func (r *Router) Register(method string, url string, handler Handler) {
 	}
 	r.RouteHandler.Handle(url, c).Methods(method)
 }
+
+// RegisterStream method is registering routes with streamed responses
+func (r *Router) RegisterStream(method string, url string, handler StreamHandlerFunc) {
+	r.RouteHandler.Handle(url, streamController{handler: handler}).Methods(method)
+}
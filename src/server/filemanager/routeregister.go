@@ -9,8 +9,41 @@ func Routes() http.Handler {
 	router.Register(http.MethodGet, "/listfiles", HandlerFunc(fileManager.ListFiles))
 	router.Register(http.MethodGet, "/wordscount", HandlerFunc(fileManager.WordCounts))
 	router.Register(http.MethodGet, "/wordsfrequency", HandlerFunc(fileManager.WordFrequency))
+	router.Register(http.MethodGet, "/wordstats", HandlerFunc(fileManager.WordStats))
+	router.Register(http.MethodGet, "/wordstatstree", HandlerFunc(fileManager.WordStatsTree))
+	router.Register(http.MethodGet, "/wordfiles", HandlerFunc(fileManager.WordFiles))
+	router.Register(http.MethodGet, "/ngrams", HandlerFunc(fileManager.NGrams))
+	router.Register(http.MethodGet, "/keywords", HandlerFunc(fileManager.Keywords))
+	router.Register(http.MethodGet, "/search", HandlerFunc(fileManager.Search))
+	router.Register(http.MethodGet, "/vocabulary", HandlerFunc(fileManager.Vocabulary))
+	router.Register(http.MethodGet, "/kwic", HandlerFunc(fileManager.KWIC))
+	router.Register(http.MethodGet, "/collocations", HandlerFunc(fileManager.Collocations))
+	router.Register(http.MethodGet, "/compare", HandlerFunc(fileManager.Compare))
+	router.Register(http.MethodGet, "/analyze", HandlerFunc(fileManager.Analyze))
+	router.Register(http.MethodGet, "/entities", HandlerFunc(fileManager.Entities))
+	router.Register(http.MethodGet, "/piireport", HandlerFunc(fileManager.PIIReport))
+	router.Register(http.MethodGet, "/pii", HandlerFunc(fileManager.PIIConfig))
+	router.Register(http.MethodPut, "/pii", HandlerFunc(fileManager.UpdatePIIConfig))
+	router.Register(http.MethodGet, "/policies", HandlerFunc(fileManager.Policies))
+	router.Register(http.MethodPut, "/policies", HandlerFunc(fileManager.UpdatePolicies))
+	router.Register(http.MethodGet, "/quarantine", HandlerFunc(fileManager.ListQuarantine))
+	router.RegisterStream(http.MethodGet, "/grep", fileManager.Grep)
+	router.RegisterStream(http.MethodGet, "/query", fileManager.Query)
+	router.RegisterStream(http.MethodGet, "/follow", fileManager.Follow)
+	router.Register(http.MethodGet, "/similar", HandlerFunc(fileManager.Similar))
+	router.Register(http.MethodGet, "/stopwords", HandlerFunc(fileManager.ListStopWords))
+	router.Register(http.MethodPut, "/stopwords", HandlerFunc(fileManager.UpdateStopWords))
+	router.Register(http.MethodDelete, "/stopwords", HandlerFunc(fileManager.RemoveStopWords))
 	router.Register(http.MethodPost, "/addfiles", HandlerFunc(fileManager.AddFiles))
 	router.Register(http.MethodPut, "/updatefiles", HandlerFunc(fileManager.UpdateFiles))
+	router.Register(http.MethodPost, "/appendfile", HandlerFunc(fileManager.AppendFile))
 	router.Register(http.MethodDelete, "/removefile", HandlerFunc(fileManager.RemoveFile))
+	router.Register(http.MethodGet, "/downloadfile", HandlerFunc(fileManager.DownloadFile))
+	router.Register(http.MethodGet, "/fileinfo", HandlerFunc(fileManager.FileInfo))
+	router.Register(http.MethodGet, "/describe", HandlerFunc(fileManager.Describe))
+	router.Register(http.MethodGet, "/lines", HandlerFunc(fileManager.Lines))
+	router.Register(http.MethodGet, "/head", HandlerFunc(fileManager.Head))
+	router.Register(http.MethodGet, "/tail", HandlerFunc(fileManager.Tail))
+	router.Register(http.MethodGet, "/diff", HandlerFunc(fileManager.Diff))
 	return router.RouteHandler
 }
@@ -0,0 +1,528 @@
+package filemanager
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// bm25 ranking parameters
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// defaultSearchLimit is the number of hits returned by default
+// maxSnippets is the number of matching lines returned per hit
+const (
+	defaultSearchLimit = 10
+	maxSnippets        = 3
+)
+
+// searchResponse is representing the ranked files matching a query
+type searchResponse struct {
+	Query string      `json:"query"`
+	Total int         `json:"total"`
+	Hits  []searchHit `json:"hits"`
+}
+
+// searchHit is representing a file matching a query
+type searchHit struct {
+	Name     string    `json:"name"`
+	Score    float64   `json:"score"`
+	Matches  int       `json:"matches"`
+	Snippets []snippet `json:"snippets"`
+}
+
+// snippet is representing a matching line of a file
+// highlights are the byte ranges of the matching words in the text
+type snippet struct {
+	Line       int         `json:"line"`
+	Text       string      `json:"text"`
+	Highlights []highlight `json:"highlights"`
+}
+
+// highlight is representing a byte range of a snippet text
+type highlight struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// queryNode is a node of a parsed search query
+// eval is returning the matching files with the positions of the matching words
+// terms is collecting the words which are not negated (used for ranking)
+type queryNode interface {
+	eval(idx *invertedIndex) map[string][]int
+	terms(words []string) []string
+}
+
+// termQuery is matching a word or a phrase (adjacent words)
+type termQuery struct {
+	words []string
+}
+
+// andQuery is matching files matching all the nodes
+type andQuery struct {
+	nodes []queryNode
+}
+
+// orQuery is matching files matching any of the nodes
+type orQuery struct {
+	nodes []queryNode
+}
+
+// notQuery is matching files not matching the node
+type notQuery struct {
+	node queryNode
+}
+
+// Search is searching the files with a query (q) of words, quoted phrases,
+// AND, OR, NOT (or -word) and parentheses, words next to each other are AND-ed
+// hits are ranked by bm25 and contain line numbered snippets
+func (fm *fileManager) Search(r *http.Request) (interface{}, error) {
+	values := r.URL.Query()
+	query := values.Get("q")
+	if strings.TrimSpace(query) == "" {
+		return nil, &statusError{code: http.StatusBadRequest, err: errors.New("search query is empty")}
+	}
+
+	limit, err := queryInt(values.Get("limit"), defaultSearchLimit)
+	if err != nil {
+		return nil, err
+	}
+	offset, err := queryInt(values.Get("offset"), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := parseQuery(query, indexAnalyzer())
+	if err != nil {
+		return nil, &statusError{code: http.StatusBadRequest, err: err}
+	}
+
+	if err := fm.index.ensureBuilt(); err != nil {
+		return nil, err
+	}
+	fm.index.mu.RLock()
+	defer fm.index.mu.RUnlock()
+
+	matches := node.eval(&fm.index)
+	terms := node.terms(nil)
+	hits := make([]searchHit, 0, len(matches))
+	for name, positions := range matches {
+		hits = append(hits, searchHit{
+			Name:    name,
+			Score:   fm.index.bm25(name, terms),
+			Matches: len(positions),
+		})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Name < hits[j].Name
+	})
+
+	searchResponse := &searchResponse{
+		Query: query,
+		Total: len(hits),
+		Hits:  []searchHit{},
+	}
+	if offset < len(hits) {
+		hits = hits[offset:]
+		if len(hits) > limit {
+			hits = hits[:limit]
+		}
+		for _, hit := range hits {
+			hit.Snippets, err = snippets(fm.index.docs[hit.Name], matches[hit.Name])
+			if err != nil {
+				return nil, err
+			}
+			searchResponse.Hits = append(searchResponse.Hits, hit)
+		}
+	}
+	return searchResponse, nil
+}
+
+// queryInt is parsing an optional positive integer query parameter
+func queryInt(value string, defaultValue int) (int, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, &statusError{code: http.StatusBadRequest, err: fmt.Errorf("invalid query parameter value %v", value)}
+	}
+	return n, nil
+}
+
+// bm25 is scoring a file for the query terms, the read lock has to be held
+func (idx *invertedIndex) bm25(name string, terms []string) float64 {
+	n := float64(len(idx.docs))
+	if n == 0 {
+		return 0
+	}
+	avgLength := float64(idx.words) / n
+	length := float64(idx.docs[name].Words)
+
+	score := 0.0
+	for _, term := range terms {
+		tf := float64(len(idx.postings[term][name]))
+		if tf == 0 {
+			continue
+		}
+		df := float64(len(idx.postings[term]))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		norm := 1 - bm25B
+		if avgLength > 0 {
+			norm += bm25B * length / avgLength
+		}
+		score += idf * tf * (bm25K1 + 1) / (tf + bm25K1*norm)
+	}
+	return score
+}
+
+// snippets is returning the first lines of a file with matching words
+func snippets(analysis *fileAnalysis, positions []int) ([]snippet, error) {
+	byLine := make(map[int][]highlight)
+	lines := []int{}
+	for _, position := range positions {
+		if position >= len(analysis.Tokens) {
+			continue
+		}
+		tok := analysis.Tokens[position]
+		if _, ok := byLine[tok.Line]; !ok {
+			if len(lines) == maxSnippets {
+				continue
+			}
+			lines = append(lines, tok.Line)
+		}
+		byLine[tok.Line] = append(byLine[tok.Line], highlight{Start: tok.Start, End: tok.End})
+	}
+	if len(lines) == 0 {
+		return []snippet{}, nil
+	}
+
+	texts, err := readLines(filepath.Join(filesDir, analysis.Name), byLine)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Ints(lines)
+	result := make([]snippet, 0, len(lines))
+	for _, line := range lines {
+		text := texts[line]
+		highlights := []highlight{}
+		for _, h := range byLine[line] {
+			if h.End <= len(text) {
+				highlights = append(highlights, h)
+			}
+		}
+		sort.Slice(highlights, func(i, j int) bool {
+			return highlights[i].Start < highlights[j].Start
+		})
+		result = append(result, snippet{Line: line, Text: text, Highlights: highlights})
+	}
+	return result, nil
+}
+
+// readLines is returning the text (without line ending) of the wanted lines of a file
+func readLines(fPath string, wanted map[int][]highlight) (map[int]string, error) {
+	file, err := openText(fPath)
+	if err != nil {
+		return nil, fmt.Errorf("error while opening file %v with error %v", fPath, err)
+	}
+	defer file.Close()
+
+	texts := make(map[int]string, len(wanted))
+	rdr := bufio.NewReader(file)
+	for lineNumber := 1; len(texts) < len(wanted); lineNumber++ {
+		line, err := rdr.ReadString('\n')
+		if _, ok := wanted[lineNumber]; ok {
+			texts[lineNumber] = strings.TrimRight(line, "\r\n")
+		}
+		if err != nil {
+			if err != io.EOF {
+				return nil, fmt.Errorf("error while reading from file %v with error %v", fPath, err)
+			}
+			break
+		}
+	}
+	return texts, nil
+}
+
+// parseQuery is parsing a search query
+func parseQuery(query string, a *analyzer) (queryNode, error) {
+	tokens, err := lexQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	p := &queryParser{tokens: tokens, analyzer: a}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek() != "" {
+		return nil, fmt.Errorf("unexpected %q in query", p.peek())
+	}
+	return node, nil
+}
+
+// lexQuery is splitting a query into words, quoted phrases and parentheses
+func lexQuery(query string) ([]string, error) {
+	tokens := []string{}
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')':
+			tokens = append(tokens, string(r))
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("unterminated phrase in query")
+			}
+			tokens = append(tokens, string(runes[i:end+1]))
+			i = end + 1
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && runes[end] != '(' && runes[end] != ')' && runes[end] != '"' {
+				end++
+			}
+			tokens = append(tokens, string(runes[i:end]))
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+// queryParser is a recursive descent parser of search queries
+type queryParser struct {
+	tokens   []string
+	pos      int
+	analyzer *analyzer
+}
+
+// peek is returning the next token or "" at the end
+func (p *queryParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+// parseOr is parsing and-expressions separated by OR
+func (p *queryParser) parseOr() (queryNode, error) {
+	node, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	nodes := []queryNode{node}
+	for p.peek() == "OR" {
+		p.pos++
+		node, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return &orQuery{nodes: nodes}, nil
+}
+
+// parseAnd is parsing not-expressions separated by AND or nothing
+func (p *queryParser) parseAnd() (queryNode, error) {
+	node, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	nodes := []queryNode{node}
+	for {
+		next := p.peek()
+		if next == "" || next == ")" || next == "OR" {
+			break
+		}
+		if next == "AND" {
+			p.pos++
+		}
+		node, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return &andQuery{nodes: nodes}, nil
+}
+
+// parseNot is parsing NOT expression and -word
+func (p *queryParser) parseNot() (queryNode, error) {
+	next := p.peek()
+	if next == "NOT" {
+		p.pos++
+		node, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notQuery{node: node}, nil
+	}
+	if len(next) > 1 && next[0] == '-' {
+		p.pos++
+		node, err := p.termQuery(next[1:])
+		if err != nil {
+			return nil, err
+		}
+		return &notQuery{node: node}, nil
+	}
+	return p.parsePrimary()
+}
+
+// parsePrimary is parsing a word, a phrase or a parenthesized expression
+func (p *queryParser) parsePrimary() (queryNode, error) {
+	next := p.peek()
+	switch next {
+	case "":
+		return nil, fmt.Errorf("unexpected end of query")
+	case ")", "AND", "OR":
+		return nil, fmt.Errorf("unexpected %q in query", next)
+	case "(":
+		p.pos++
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing ) in query")
+		}
+		p.pos++
+		return node, nil
+	}
+	p.pos++
+	return p.termQuery(strings.Trim(next, `"`))
+}
+
+// termQuery is creating a query of the words of text
+// text with more than one word is a phrase
+func (p *queryParser) termQuery(text string) (queryNode, error) {
+	words := []string{}
+	for _, tok := range p.analyzer.words(text) {
+		words = append(words, tok.Text)
+	}
+	if len(words) == 0 {
+		return nil, fmt.Errorf("query term %q has no words", text)
+	}
+	return &termQuery{words: words}, nil
+}
+
+// eval is returning the files containing the word or phrase
+func (q *termQuery) eval(idx *invertedIndex) map[string][]int {
+	matches := make(map[string][]int)
+	for name, starts := range idx.postings[q.words[0]] {
+		positions := []int{}
+		for _, start := range starts {
+			if idx.phraseAt(name, q.words, start) {
+				for i := range q.words {
+					positions = append(positions, start+i)
+				}
+			}
+		}
+		if len(positions) > 0 {
+			matches[name] = positions
+		}
+	}
+	return matches
+}
+
+// phraseAt is checking whether the words follow each other from start
+func (idx *invertedIndex) phraseAt(name string, words []string, start int) bool {
+	tokens := idx.docs[name].Tokens
+	if start+len(words) > len(tokens) {
+		return false
+	}
+	for i, word := range words {
+		if tokens[start+i].Word != word {
+			return false
+		}
+	}
+	return true
+}
+
+func (q *termQuery) terms(words []string) []string {
+	return append(words, q.words...)
+}
+
+// eval is returning the files matching all the nodes
+func (q *andQuery) eval(idx *invertedIndex) map[string][]int {
+	matches := q.nodes[0].eval(idx)
+	for _, node := range q.nodes[1:] {
+		next := node.eval(idx)
+		for name, positions := range matches {
+			other, ok := next[name]
+			if !ok {
+				delete(matches, name)
+				continue
+			}
+			matches[name] = append(positions, other...)
+		}
+	}
+	return matches
+}
+
+func (q *andQuery) terms(words []string) []string {
+	for _, node := range q.nodes {
+		words = node.terms(words)
+	}
+	return words
+}
+
+// eval is returning the files matching any of the nodes
+func (q *orQuery) eval(idx *invertedIndex) map[string][]int {
+	matches := make(map[string][]int)
+	for _, node := range q.nodes {
+		for name, positions := range node.eval(idx) {
+			matches[name] = append(matches[name], positions...)
+		}
+	}
+	return matches
+}
+
+func (q *orQuery) terms(words []string) []string {
+	for _, node := range q.nodes {
+		words = node.terms(words)
+	}
+	return words
+}
+
+// eval is returning all the files which do not match the node
+func (q *notQuery) eval(idx *invertedIndex) map[string][]int {
+	excluded := q.node.eval(idx)
+	matches := make(map[string][]int)
+	for _, name := range idx.docNames() {
+		if _, ok := excluded[name]; !ok {
+			matches[name] = nil
+		}
+	}
+	return matches
+}
+
+func (q *notQuery) terms(words []string) []string {
+	return words
+}
@@ -0,0 +1,246 @@
+package filemanager
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// quarantineDir is the directory where quarantined uploads are stored
+var quarantineDir = "../quarantine"
+
+// high entropy strings are random looking runs of base64 or hex characters
+// the entropy is the shannon entropy in bits per character
+const (
+	minBase64SecretLength = 20
+	minHexSecretLength    = 32
+	base64SecretEntropy   = 4.0
+	hexSecretEntropy      = 3.0
+)
+
+// secretKind is a known key or token format
+type secretKind struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+// secretKinds are the known key and token formats, earlier kinds win
+// when matches overlap
+var secretKinds = []secretKind{
+	{name: "private_key", pattern: regexp.MustCompile(`-----BEGIN (?:[A-Z0-9]+ )*PRIVATE KEY(?: BLOCK)?-----`)},
+	{name: "aws_access_key", pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{name: "github_token", pattern: regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`)},
+	{name: "slack_token", pattern: regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`)},
+	{name: "google_api_key", pattern: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}`)},
+	{name: "stripe_key", pattern: regexp.MustCompile(`\b[rs]k_live_[0-9A-Za-z]{24,}\b`)},
+	{name: "jwt", pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}`)},
+}
+
+// secretCandidate is a run of characters which may be a random secret
+var secretCandidate = regexp.MustCompile(`[A-Za-z0-9+/_=-]{20,}`)
+
+// secretFinding is representing a secret found in a file
+// Kind is a known format or high_entropy, Text is masked like pii
+type secretFinding struct {
+	Kind  string `json:"kind"`
+	Line  int    `json:"line"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Text  string `json:"text"`
+}
+
+// secretFileReport is representing the secrets found in an uploaded file
+// and the action of the policy of its namespace
+type secretFileReport struct {
+	Name      string          `json:"name"`
+	Namespace string          `json:"namespace"`
+	Action    string          `json:"action"`
+	Findings  []secretFinding `json:"findings"`
+}
+
+// checkSecrets is scanning uploaded files for keys, tokens and high entropy
+// strings before they are written, every detection is logged
+// when the policy of a namespace rejects secrets none of the files is stored
+func checkSecrets(files []file) ([]secretFileReport, error) {
+	policies, err := loadPolicies()
+	if err != nil {
+		return nil, err
+	}
+
+	reports := []secretFileReport{}
+	rejected := []string{}
+	for _, file := range files {
+		findings := findSecrets(uploadedText(file))
+		if len(findings) == 0 {
+			continue
+		}
+		report := secretFileReport{
+			Name:      file.Name,
+			Namespace: namespaceOf(file.Name),
+			Action:    policies.policyFor(file.Name).Secrets,
+			Findings:  findings,
+		}
+		for _, finding := range findings {
+			log.Printf("secret detected file=%v namespace=%q kind=%v line=%v action=%v",
+				report.Name, report.Namespace, finding.Kind, finding.Line, report.Action)
+		}
+		if report.Action == secretActionReject {
+			rejected = append(rejected, report.Name)
+		}
+		reports = append(reports, report)
+	}
+
+	if len(rejected) > 0 {
+		return nil, &statusError{
+			code: http.StatusUnprocessableEntity,
+			err:  fmt.Errorf("files rejected, secrets found in %v", strings.Join(rejected, ", ")),
+		}
+	}
+	return reports, nil
+}
+
+// quarantined is checking whether the policy quarantines an uploaded file
+func quarantined(reports []secretFileReport, name string) bool {
+	for _, report := range reports {
+		if report.Name == name && report.Action == secretActionQuarantine {
+			return true
+		}
+	}
+	return false
+}
+
+// quarantineFile is storing an uploaded file in the quarantine instead of the files
+func quarantineFile(fileDetail file) error {
+	filePath, err := filepath.Abs(filepath.Join(quarantineDir, fileDetail.Name))
+	if err != nil {
+		return fmt.Errorf("error while creating quarantine path %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("creating quarantine directory failed with %v", err)
+	}
+	if err := ioutil.WriteFile(filePath, fileDetail.Content, 0644); err != nil {
+		return fmt.Errorf("quarantine file failed with error %v", err)
+	}
+	return nil
+}
+
+// ListQuarantine is returning the names of the quarantined files
+func (fm *fileManager) ListQuarantine(_ *http.Request) (interface{}, error) {
+	names := []string{}
+	paths, err := readDir(quarantineDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return names, nil
+		}
+		return nil, err
+	}
+
+	base, err := filepath.Abs(quarantineDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, fPath := range paths {
+		absPath, err := filepath.Abs(fPath)
+		if err != nil {
+			return nil, err
+		}
+		name, err := filepath.Rel(base, absPath)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, filepath.ToSlash(name))
+	}
+	return names, nil
+}
+
+// findSecrets is returning the keys, tokens and high entropy strings of the content
+func findSecrets(content []byte) []secretFinding {
+	findings := []secretFinding{}
+	eachLine(content, func(lineNumber int, line string) {
+		for _, m := range matchSecrets(line) {
+			findings = append(findings, secretFinding{
+				Kind:  m.rule,
+				Line:  lineNumber,
+				Start: m.start,
+				End:   m.end,
+				Text:  maskPII(line[m.start:m.end]),
+			})
+		}
+	})
+	return findings
+}
+
+// matchSecrets is returning the secrets of a line in the order they appear
+func matchSecrets(line string) []piiMatch {
+	matches := []piiMatch{}
+	taken := func(start, end int) bool {
+		for _, m := range matches {
+			if start < m.end && m.start < end {
+				return true
+			}
+		}
+		return false
+	}
+	for _, kind := range secretKinds {
+		for _, loc := range kind.pattern.FindAllStringIndex(line, -1) {
+			if !taken(loc[0], loc[1]) {
+				matches = append(matches, piiMatch{rule: kind.name, start: loc[0], end: loc[1]})
+			}
+		}
+	}
+	for _, loc := range secretCandidate.FindAllStringIndex(line, -1) {
+		if !taken(loc[0], loc[1]) && highEntropy(line[loc[0]:loc[1]]) {
+			matches = append(matches, piiMatch{rule: "high_entropy", start: loc[0], end: loc[1]})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].start < matches[j].start
+	})
+	return matches
+}
+
+// highEntropy is checking whether a string looks random
+// hex strings have a lower threshold as they use only 16 characters and
+// other strings need letters and digits so that long words are not secrets
+func highEntropy(s string) bool {
+	if isHex(s) {
+		return len(s) >= minHexSecretLength && shannonEntropy(s) >= hexSecretEntropy
+	}
+	if len(s) < minBase64SecretLength || !strings.ContainsAny(s, "0123456789") {
+		return false
+	}
+	return shannonEntropy(s) >= base64SecretEntropy
+}
+
+// shannonEntropy is the entropy of the characters of s in bits per character
+func shannonEntropy(s string) float64 {
+	counts := make(map[rune]int)
+	for _, r := range s {
+		counts[r]++
+	}
+	entropy := 0.0
+	n := float64(len(s))
+	for _, count := range counts {
+		p := float64(count) / n
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// isHex is checking whether s has only hexadecimal digits
+func isHex(s string) bool {
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,245 @@
+package filemanager
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// wordStatsRequest is representing the word statistics request
+type wordStatsRequest struct {
+	analysisOptions
+}
+
+// wordStats is representing wc style statistics
+type wordStats struct {
+	Words             int     `json:"words"`
+	UniqueWords       int     `json:"unique_words"`
+	Lines             int     `json:"lines"`
+	Characters        int     `json:"characters"`
+	Bytes             int     `json:"bytes"`
+	AverageWordLength float64 `json:"average_word_length"`
+}
+
+// fileWordStats is representing statistics of a single file
+type fileWordStats struct {
+	Name string `json:"name"`
+	wordStats
+}
+
+// wordStatsResponse is representing statistics per file and for all of them
+type wordStatsResponse struct {
+	Files []fileWordStats `json:"files"`
+	Total wordStats       `json:"total"`
+}
+
+// stats is returning the wc style statistics of the analysis
+func (fa *fileAnalysis) stats() wordStats {
+	return wordStats{
+		Words:             fa.Words,
+		UniqueWords:       len(fa.Counts),
+		Lines:             fa.Lines,
+		Characters:        fa.Chars,
+		Bytes:             fa.Bytes,
+		AverageWordLength: averageWordLength(fa.WordLength, fa.Words),
+	}
+}
+
+// directoryStats is representing statistics of a directory and everything below it
+// Name is the directory path with a trailing slash, the root directory is ""
+type directoryStats struct {
+	Name string `json:"name"`
+	wordStats
+	Files       []fileWordStats   `json:"files"`
+	Directories []*directoryStats `json:"directories"`
+}
+
+// wordFilesRequest is representing the request of files contributing to a word
+type wordFilesRequest struct {
+	analysisOptions
+	Word  string `json:"word"`
+	Limit int    `json:"limit"`
+}
+
+// wordFile is representing the count of a word in a file
+// Share is the part of all the occurrences of the word found in the file
+type wordFile struct {
+	Name  string  `json:"name"`
+	Count int     `json:"count"`
+	Share float64 `json:"share"`
+}
+
+// wordFilesResponse is representing files contributing to a word, most first
+type wordFilesResponse struct {
+	Word  string     `json:"word"`
+	Count int        `json:"count"`
+	Files []wordFile `json:"files"`
+}
+
+// mergeCounts is adding up word counts of all the analyses
+func mergeCounts(analyses []*fileAnalysis) map[string]int {
+	wordCounts := make(map[string]int)
+	for _, analysis := range analyses {
+		for word, count := range analysis.Counts {
+			wordCounts[word] += count
+		}
+	}
+	return wordCounts
+}
+
+// totalStats is returning the statistics of all the analyses together
+func totalStats(analyses []*fileAnalysis) wordStats {
+	total := newFileAnalysis("")
+	total.Counts = mergeCounts(analyses)
+	for _, analysis := range analyses {
+		total.Words += analysis.Words
+		total.Lines += analysis.Lines
+		total.Chars += analysis.Chars
+		total.Bytes += analysis.Bytes
+		total.WordLength += analysis.WordLength
+	}
+	return total.stats()
+}
+
+// averageWordLength is returning the average length of a word in characters
+func averageWordLength(length, words int) float64 {
+	if words == 0 {
+		return 0
+	}
+	return float64(length) / float64(words)
+}
+
+// WordStats is returning wc style statistics (words, unique words, lines,
+// characters, bytes and average word length) for each file and all the files
+func (fm *fileManager) WordStats(r *http.Request) (interface{}, error) {
+	wordStatsRequest := &wordStatsRequest{}
+	if err := decodeBody(r, wordStatsRequest); err != nil {
+		return nil, fmt.Errorf("word stats request body decoding failed with %v", err)
+	}
+
+	analyzer, err := newAnalyzer(wordStatsRequest.analysisOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	analyses, err := analyzeFiles(analyzer)
+	if err != nil {
+		return nil, err
+	}
+
+	wordStatsResponse := &wordStatsResponse{
+		Files: make([]fileWordStats, 0, len(analyses)),
+		Total: totalStats(analyses),
+	}
+	for _, analysis := range analyses {
+		wordStatsResponse.Files = append(wordStatsResponse.Files, fileWordStats{
+			Name:      analysis.Name,
+			wordStats: analysis.stats(),
+		})
+	}
+	return wordStatsResponse, nil
+}
+
+// WordStatsTree is returning word statistics rolled up per directory
+func (fm *fileManager) WordStatsTree(r *http.Request) (interface{}, error) {
+	wordStatsRequest := &wordStatsRequest{}
+	if err := decodeBody(r, wordStatsRequest); err != nil {
+		return nil, fmt.Errorf("word stats request body decoding failed with %v", err)
+	}
+
+	analyzer, err := newAnalyzer(wordStatsRequest.analysisOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	analyses, err := analyzeFiles(analyzer)
+	if err != nil {
+		return nil, err
+	}
+	return statsTree("", analyses), nil
+}
+
+// statsTree is returning the statistics of the directory dir
+// analyses are the (sorted) analyses of all the files below dir
+func statsTree(dir string, analyses []*fileAnalysis) *directoryStats {
+	directory := &directoryStats{
+		Name:        dir,
+		wordStats:   totalStats(analyses),
+		Files:       []fileWordStats{},
+		Directories: []*directoryStats{},
+	}
+
+	subdirs := []string{}
+	below := make(map[string][]*fileAnalysis)
+	for _, analysis := range analyses {
+		rest := strings.TrimPrefix(analysis.Name, dir)
+		i := strings.Index(rest, "/")
+		if i < 0 {
+			directory.Files = append(directory.Files, fileWordStats{
+				Name:      analysis.Name,
+				wordStats: analysis.stats(),
+			})
+			continue
+		}
+		subdir := dir + rest[:i+1]
+		if _, ok := below[subdir]; !ok {
+			subdirs = append(subdirs, subdir)
+		}
+		below[subdir] = append(below[subdir], analysis)
+	}
+
+	for _, subdir := range subdirs {
+		directory.Directories = append(directory.Directories, statsTree(subdir, below[subdir]))
+	}
+	return directory
+}
+
+// WordFiles is returning the files in which a word occurs, the files
+// with the most occurrences first
+func (fm *fileManager) WordFiles(r *http.Request) (interface{}, error) {
+	wordFilesRequest := &wordFilesRequest{}
+	if err := decodeBody(r, wordFilesRequest); err != nil {
+		return nil, fmt.Errorf("word files request body decoding failed with %v", err)
+	}
+
+	analyzer, err := newAnalyzer(wordFilesRequest.analysisOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	word, err := analyzer.term(wordFilesRequest.Word)
+	if err != nil {
+		return nil, err
+	}
+
+	analyses, err := analyzeFiles(analyzer)
+	if err != nil {
+		return nil, err
+	}
+
+	wordFilesResponse := &wordFilesResponse{
+		Word:  word,
+		Files: []wordFile{},
+	}
+	for _, analysis := range analyses {
+		if count := analysis.Counts[word]; count > 0 {
+			wordFilesResponse.Count += count
+			wordFilesResponse.Files = append(wordFilesResponse.Files, wordFile{
+				Name:  analysis.Name,
+				Count: count,
+			})
+		}
+	}
+
+	sort.SliceStable(wordFilesResponse.Files, func(i, j int) bool {
+		return wordFilesResponse.Files[i].Count > wordFilesResponse.Files[j].Count
+	})
+	for i := range wordFilesResponse.Files {
+		wordFilesResponse.Files[i].Share = float64(wordFilesResponse.Files[i].Count) / float64(wordFilesResponse.Count)
+	}
+	if wordFilesRequest.Limit > 0 && wordFilesRequest.Limit < len(wordFilesResponse.Files) {
+		wordFilesResponse.Files = wordFilesResponse.Files[:wordFilesRequest.Limit]
+	}
+	return wordFilesResponse, nil
+}
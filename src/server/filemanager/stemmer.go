@@ -0,0 +1,262 @@
+package filemanager
+
+// step2Suffixes, step3Suffixes are suffix replacements of the porter stemmer
+// they are checked in order and only the first matching suffix is used
+var (
+	step2Suffixes = [][2]string{
+		{"ational", "ate"}, {"tional", "tion"},
+		{"enci", "ence"}, {"anci", "ance"},
+		{"izer", "ize"},
+		{"bli", "ble"}, {"alli", "al"}, {"entli", "ent"}, {"eli", "e"}, {"ousli", "ous"},
+		{"ization", "ize"}, {"ation", "ate"}, {"ator", "ate"},
+		{"alism", "al"}, {"iveness", "ive"}, {"fulness", "ful"}, {"ousness", "ous"},
+		{"aliti", "al"}, {"iviti", "ive"}, {"biliti", "ble"},
+		{"logi", "log"},
+	}
+	step3Suffixes = [][2]string{
+		{"icate", "ic"}, {"ative", ""}, {"alize", "al"},
+		{"iciti", "ic"},
+		{"ical", "ic"}, {"ful", ""},
+		{"ness", ""},
+	}
+	step4Suffixes = []string{
+		"al", "ance", "ence", "er", "ic", "able", "ible", "ant", "ement", "ment", "ent",
+		"ion", "ou", "ism", "ate", "iti", "ous", "ive", "ize",
+	}
+)
+
+// porterStemmer is reducing english words to their stem with the
+// porter stemming algorithm
+// k is the index of the last letter and j the end of the stem once a suffix matched
+type porterStemmer struct {
+	b []byte
+	k int
+	j int
+}
+
+// stem is returning the stem of a lower case english word
+// words with other letters than a-z are returned as they are
+func stem(word string) string {
+	if len(word) <= 2 {
+		return word
+	}
+	for i := 0; i < len(word); i++ {
+		if word[i] < 'a' || word[i] > 'z' {
+			return word
+		}
+	}
+
+	p := &porterStemmer{b: []byte(word), k: len(word) - 1}
+	p.step1ab()
+	if p.k > 0 {
+		p.step1c()
+		p.step2()
+		p.step3()
+		p.step4()
+		p.step5()
+	}
+	return string(p.b[:p.k+1])
+}
+
+// cons is checking whether the letter at i is a consonant
+func (p *porterStemmer) cons(i int) bool {
+	switch p.b[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return false
+	case 'y':
+		if i == 0 {
+			return true
+		}
+		return !p.cons(i - 1)
+	}
+	return true
+}
+
+// m is measuring the number of vowel consonant sequences between 0 and j
+func (p *porterStemmer) m() int {
+	n := 0
+	i := 0
+	for {
+		if i > p.j {
+			return n
+		}
+		if !p.cons(i) {
+			break
+		}
+		i++
+	}
+	i++
+	for {
+		for {
+			if i > p.j {
+				return n
+			}
+			if p.cons(i) {
+				break
+			}
+			i++
+		}
+		i++
+		n++
+		for {
+			if i > p.j {
+				return n
+			}
+			if !p.cons(i) {
+				break
+			}
+			i++
+		}
+		i++
+	}
+}
+
+// vowelInStem is checking whether the stem (0 to j) contains a vowel
+func (p *porterStemmer) vowelInStem() bool {
+	for i := 0; i <= p.j; i++ {
+		if !p.cons(i) {
+			return true
+		}
+	}
+	return false
+}
+
+// doubleC is checking whether j and j-1 are the same consonant
+func (p *porterStemmer) doubleC(j int) bool {
+	if j < 1 || p.b[j] != p.b[j-1] {
+		return false
+	}
+	return p.cons(j)
+}
+
+// cvc is checking whether i-2, i-1, i is consonant vowel consonant
+// and the last consonant is not w, x or y
+func (p *porterStemmer) cvc(i int) bool {
+	if i < 2 || !p.cons(i) || p.cons(i-1) || !p.cons(i-2) {
+		return false
+	}
+	switch p.b[i] {
+	case 'w', 'x', 'y':
+		return false
+	}
+	return true
+}
+
+// ends is checking whether the word ends with s and sets j to the end of the stem
+func (p *porterStemmer) ends(s string) bool {
+	l := len(s)
+	if l > p.k+1 || string(p.b[p.k-l+1:p.k+1]) != s {
+		return false
+	}
+	p.j = p.k - l
+	return true
+}
+
+// setTo is replacing the letters after j with s
+func (p *porterStemmer) setTo(s string) {
+	p.b = append(p.b[:p.j+1], s...)
+	p.k = p.j + len(s)
+}
+
+// r is replacing the suffix with s when the stem has a vowel consonant sequence
+func (p *porterStemmer) r(s string) {
+	if p.m() > 0 {
+		p.setTo(s)
+	}
+}
+
+// step1ab is removing plurals and -ed or -ing
+func (p *porterStemmer) step1ab() {
+	if p.b[p.k] == 's' {
+		switch {
+		case p.ends("sses"):
+			p.k -= 2
+		case p.ends("ies"):
+			p.setTo("i")
+		case p.b[p.k-1] != 's':
+			p.k--
+		}
+	}
+	if p.ends("eed") {
+		if p.m() > 0 {
+			p.k--
+		}
+		return
+	}
+	if (p.ends("ed") || p.ends("ing")) && p.vowelInStem() {
+		p.k = p.j
+		switch {
+		case p.ends("at"):
+			p.setTo("ate")
+		case p.ends("bl"):
+			p.setTo("ble")
+		case p.ends("iz"):
+			p.setTo("ize")
+		case p.doubleC(p.k):
+			p.k--
+			switch p.b[p.k] {
+			case 'l', 's', 'z':
+				p.k++
+			}
+		case p.m() == 1 && p.cvc(p.k):
+			p.setTo("e")
+		}
+	}
+}
+
+// step1c is turning terminal y to i when there is another vowel in the stem
+func (p *porterStemmer) step1c() {
+	if p.ends("y") && p.vowelInStem() {
+		p.b[p.k] = 'i'
+	}
+}
+
+// step2 is mapping double suffixes to single ones
+func (p *porterStemmer) step2() {
+	for _, suffix := range step2Suffixes {
+		if p.ends(suffix[0]) {
+			p.r(suffix[1])
+			return
+		}
+	}
+}
+
+// step3 is dealing with -ic-, -full, -ness etc.
+func (p *porterStemmer) step3() {
+	for _, suffix := range step3Suffixes {
+		if p.ends(suffix[0]) {
+			p.r(suffix[1])
+			return
+		}
+	}
+}
+
+// step4 is removing -ant, -ence etc. in context <c>vcvc<v>
+func (p *porterStemmer) step4() {
+	for _, suffix := range step4Suffixes {
+		if !p.ends(suffix) {
+			continue
+		}
+		if suffix == "ion" && (p.j < 0 || (p.b[p.j] != 's' && p.b[p.j] != 't')) {
+			continue
+		}
+		if p.m() > 1 {
+			p.k = p.j
+		}
+		return
+	}
+}
+
+// step5 is removing a final -e and changing -ll to -l when m() > 1
+func (p *porterStemmer) step5() {
+	p.j = p.k
+	if p.b[p.k] == 'e' {
+		a := p.m()
+		if a > 1 || a == 1 && !p.cvc(p.k-1) {
+			p.k--
+		}
+	}
+	if p.b[p.k] == 'l' && p.doubleC(p.k) && p.m() > 1 {
+		p.k--
+	}
+}
@@ -0,0 +1,32 @@
+package filemanager
+
+import "testing"
+
+func Test_stem(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{word: "caresses", want: "caress"},
+		{word: "ponies", want: "poni"},
+		{word: "cats", want: "cat"},
+		{word: "agreed", want: "agre"},
+		{word: "hopping", want: "hop"},
+		{word: "filing", want: "file"},
+		{word: "happy", want: "happi"},
+		{word: "relational", want: "relat"},
+		{word: "generalization", want: "gener"},
+		{word: "hopefulness", want: "hope"},
+		{word: "adjustment", want: "adjust"},
+		{word: "controll", want: "control"},
+		{word: "is", want: "is"},
+		{word: "café", want: "café"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			if got := stem(tt.word); got != tt.want {
+				t.Errorf("stem() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package filemanager
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// stopWordsDir is the directory where uploaded stop word lists are stored
+var stopWordsDir = "../stopwords"
+
+// stopWordListName is the allowed name of an uploaded stop word list
+var stopWordListName = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// builtinStopWords are the stop word lists available by language code
+var builtinStopWords = map[string]string{
+	"en": `a about above after again against all am an and any are as at be because been before
+		being below between both but by can could did do does doing down during each few for from
+		further had has have having he her here hers herself him himself his how i if in into is it
+		its itself just me more most my myself no nor not now of off on once only or other our ours
+		ourselves out over own same she should so some such than that the their theirs them
+		themselves then there these they this those through to too under until up very was we were
+		what when where which while who whom why will with would you your yours yourself yourselves`,
+	"es": `a al algo algunas algunos ante antes como con contra cual cuando de del desde donde
+		durante e el ella ellas ellos en entre era esa esas ese eso esos esta estas este esto estos
+		fue ha hay la las le les lo los mas me mi mis mucho muy más nada ni no nos nosotros o os otra
+		otros para pero poco por porque que quien se ser si sin sobre su sus también te tiene todo
+		tu tus un una uno unos y ya yo`,
+	"fr": `à au aux avec ce ces c'est dans de des du elle elles en est et eux il ils je la le les
+		leur leurs lui ma mais me même mes moi mon ne nos notre nous on ou où par pas pour qu que
+		qui sa se ses son sur ta te tes toi ton tu un une vos votre vous y été être avoir a ont
+		était sont cette cet l d j n s t`,
+	"de": `aber alle allem allen aller alles als also am an auch auf aus bei bin bis bist da damit
+		dann das dass dem den der des dich die dir doch dort du durch ein eine einem einen einer
+		eines er es für hat hatte hier ich ihr ihre im in ist ja jede kann kein man mich mir mit
+		nach nicht noch nun nur ob oder ohne sein sich sie sind so über um und uns unter vom von
+		vor war was weil wenn wer wie wir wird zu zum zur`,
+	"it": `a ad al alla alle anche che chi ci come con cosa da dal dalla dei del della delle di
+		dove e ed era gli ha hanno i il in io la le lei lo loro lui ma mi mio ne nei nel nella noi
+		non o per più quale quando questa questo se si sono su sua suo tra tu un una uno vi voi è`,
+	"pt": `a ao aos as até com como da das de dela dele do dos e ela elas ele eles em entre era
+		essa esse esta este eu foi há isso isto já lhe mais mas me mesmo meu minha muito na nas
+		nem no nos nós o os ou para pela pelo por qual que quem se sem seu sua são também te tem
+		um uma você à é`,
+}
+
+// stopWordList is representing an uploaded stop word list
+type stopWordList struct {
+	Name  string   `json:"name"`
+	Words []string `json:"words"`
+}
+
+// stopWordListsResponse is representing the available stop word lists
+type stopWordListsResponse struct {
+	Builtin  []string `json:"builtin"`
+	Uploaded []string `json:"uploaded"`
+}
+
+// ListStopWords is returning the names of built in and uploaded stop word lists
+func (fm *fileManager) ListStopWords(_ *http.Request) (interface{}, error) {
+	response := &stopWordListsResponse{
+		Builtin:  make([]string, 0, len(builtinStopWords)),
+		Uploaded: []string{},
+	}
+	for name := range builtinStopWords {
+		response.Builtin = append(response.Builtin, name)
+	}
+	sort.Strings(response.Builtin)
+
+	entries, err := ioutil.ReadDir(stopWordsDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("reading stop word lists failed with %v", err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".txt") {
+			response.Uploaded = append(response.Uploaded, strings.TrimSuffix(entry.Name(), ".txt"))
+		}
+	}
+	return response, nil
+}
+
+// UpdateStopWords is creating/replacing an uploaded stop word list
+func (fm *fileManager) UpdateStopWords(r *http.Request) (interface{}, error) {
+	list := &stopWordList{}
+	if err := decodeBody(r, list); err != nil {
+		return nil, fmt.Errorf("stop words request body decoding failed with %v", err)
+	}
+
+	listPath, err := stopWordListPath(list.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(stopWordsDir, 0777); err != nil {
+		return nil, fmt.Errorf("create stop words directory failed with error %v", err)
+	}
+
+	content := strings.Join(list.Words, "\n") + "\n"
+	if err := ioutil.WriteFile(listPath, []byte(content), 0666); err != nil {
+		return nil, fmt.Errorf("write stop words failed with error %v", err)
+	}
+	return nil, nil
+}
+
+// RemoveStopWords is deleting an uploaded stop word list
+func (fm *fileManager) RemoveStopWords(r *http.Request) (interface{}, error) {
+	list := &stopWordList{}
+	if err := decodeBody(r, list); err != nil {
+		return nil, fmt.Errorf("stop words request body decoding failed with %v", err)
+	}
+
+	listPath, err := stopWordListPath(list.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Remove(listPath); err != nil {
+		return nil, fmt.Errorf("delete stop words failed with error %v", err)
+	}
+	return nil, nil
+}
+
+// stopWordListPath is returning the path of an uploaded stop word list
+// built in list names can not be used
+func stopWordListPath(name string) (string, error) {
+	if !stopWordListName.MatchString(name) {
+		return "", fmt.Errorf("invalid stop word list name %v", name)
+	}
+	if _, ok := builtinStopWords[name]; ok {
+		return "", fmt.Errorf("stop word list %v is built in", name)
+	}
+	return filepath.Join(stopWordsDir, name+".txt"), nil
+}
+
+// loadStopWords is loading the named stop word lists into a set
+// words are normalized the same way as the words they are compared to
+func loadStopWords(names []string, options tokenizerOptions) (map[string]bool, error) {
+	stopWords := make(map[string]bool)
+	for _, name := range names {
+		words, err := readStopWordList(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, word := range words {
+			stopWords[normalizeWord(word, options)] = true
+		}
+	}
+	return stopWords, nil
+}
+
+// readStopWordList is returning the words of a built in or uploaded list
+func readStopWordList(name string) ([]string, error) {
+	if words, ok := builtinStopWords[name]; ok {
+		return strings.Fields(words), nil
+	}
+
+	listPath, err := stopWordListPath(name)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(listPath)
+	if err != nil {
+		return nil, fmt.Errorf("stop word list %v does not exist", name)
+	}
+	defer file.Close()
+
+	words := []string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		words = append(words, strings.Fields(scanner.Text())...)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error while reading stop word list %v with error %v", name, err)
+	}
+	return words, nil
+}
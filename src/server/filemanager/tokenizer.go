@@ -0,0 +1,181 @@
+package filemanager
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Tokenizer is splitting a line of text into words
+type Tokenizer interface {
+	Tokenize(text string) []token
+}
+
+// token is a word with its byte position in the text it was read from
+// Text is the word as it appears in the text until it is normalized
+// Stop is marking a word of a stop word list
+// Entity is the entity type (url, email, mention, hashtag) of an entity
+type token struct {
+	Text   string
+	Start  int
+	End    int
+	Stop   bool
+	Entity string
+}
+
+// tokenizerOptions is selecting and configuring the tokenizer of a request
+// Name is unicode (default), whitespace or regex
+// Normalization is nfc (default), nfkc or none
+// Entities is keeping urls, emails, mentions and hashtags as single words
+type tokenizerOptions struct {
+	Name          string `json:"name"`
+	Pattern       string `json:"pattern"`
+	Normalization string `json:"normalization"`
+	KeepCase      bool   `json:"keep_case"`
+	Entities      bool   `json:"entities"`
+}
+
+// newTokenizer is creating the tokenizer selected by the options
+func newTokenizer(options tokenizerOptions) (Tokenizer, error) {
+	words, err := newWordTokenizer(options)
+	if err != nil || !options.Entities {
+		return words, err
+	}
+	return entityTokenizer{words: words}, nil
+}
+
+// newWordTokenizer is creating the words tokenizer selected by the options
+func newWordTokenizer(options tokenizerOptions) (Tokenizer, error) {
+	switch options.Name {
+	case "", "unicode":
+		return unicodeTokenizer{}, nil
+	case "whitespace":
+		return whitespaceTokenizer{}, nil
+	case "regex":
+		if options.Pattern == "" {
+			return nil, fmt.Errorf("regex tokenizer requires a pattern")
+		}
+		pattern, err := regexp.Compile(options.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tokenizer pattern %v with error %v", options.Pattern, err)
+		}
+		return regexTokenizer{pattern: pattern}, nil
+	default:
+		return nil, fmt.Errorf("invalid tokenizer %v", options.Name)
+	}
+}
+
+// whitespaceTokenizer is splitting text on white space only
+type whitespaceTokenizer struct{}
+
+// Tokenize is returning all the white space separated words
+func (whitespaceTokenizer) Tokenize(text string) []token {
+	tokens := []token{}
+	start := -1
+	for i, r := range text {
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				tokens = append(tokens, token{Text: text[start:i], Start: start, End: i})
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{Text: text[start:], Start: start, End: len(text)})
+	}
+	return tokens
+}
+
+// unicodeTokenizer is splitting text into runs of letters, marks and numbers
+// punctuation is dropped, apostrophes inside a word are kept ("don't")
+// and ideographs which are written without spaces are single words
+type unicodeTokenizer struct{}
+
+// Tokenize is returning all the words of the text
+func (unicodeTokenizer) Tokenize(text string) []token {
+	tokens := []token{}
+	start := -1
+	emit := func(end int) {
+		if start >= 0 {
+			tokens = append(tokens, token{Text: text[start:end], Start: start, End: end})
+			start = -1
+		}
+	}
+
+	for i, r := range text {
+		switch {
+		case isIdeograph(r):
+			emit(i)
+			end := i + utf8.RuneLen(r)
+			tokens = append(tokens, token{Text: text[i:end], Start: i, End: end})
+		case isWordRune(r):
+			if start < 0 {
+				start = i
+			}
+		case isApostrophe(r) && start >= 0:
+			next, _ := utf8.DecodeRuneInString(text[i+utf8.RuneLen(r):])
+			if !isWordRune(next) || isIdeograph(next) {
+				emit(i)
+			}
+		default:
+			emit(i)
+		}
+	}
+	emit(len(text))
+	return tokens
+}
+
+// regexTokenizer is returning every match of a pattern as a word
+type regexTokenizer struct {
+	pattern *regexp.Regexp
+}
+
+// Tokenize is returning all the matches of the pattern
+func (rt regexTokenizer) Tokenize(text string) []token {
+	tokens := []token{}
+	for _, loc := range rt.pattern.FindAllStringIndex(text, -1) {
+		if loc[0] == loc[1] {
+			continue
+		}
+		tokens = append(tokens, token{Text: text[loc[0]:loc[1]], Start: loc[0], End: loc[1]})
+	}
+	return tokens
+}
+
+// isWordRune is checking whether a rune is part of a word
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsMark(r)
+}
+
+// isIdeograph is checking whether a rune belongs to a script written without spaces
+func isIdeograph(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana)
+}
+
+// isApostrophe is checking whether a rune is an apostrophe
+func isApostrophe(r rune) bool {
+	return r == '\'' || r == '’'
+}
+
+// normalizeWord is bringing a word into its canonical form
+// apostrophes are unified, unicode is normalized and case is folded
+func normalizeWord(word string, options tokenizerOptions) string {
+	word = strings.Replace(word, "’", "'", -1)
+	switch options.Normalization {
+	case "nfkc":
+		word = composeNFKC(word)
+	case "none":
+	default:
+		word = composeNFC(word)
+	}
+	if !options.KeepCase {
+		word = foldCase(word)
+	}
+	return word
+}
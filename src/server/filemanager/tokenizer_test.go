@@ -0,0 +1,220 @@
+package filemanager
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func wordTexts(tokens []token) []string {
+	texts := []string{}
+	for _, tok := range tokens {
+		if !tok.Stop {
+			texts = append(texts, tok.Text)
+		}
+	}
+	return texts
+}
+
+func Test_analyzer_words(t *testing.T) {
+	tests := []struct {
+		name    string
+		options analysisOptions
+		line    string
+		want    []string
+		wantErr bool
+	}{
+		{name: "punctuation is stripped",
+			line: "Hello, hello! (world)",
+			want: []string{"hello", "hello", "world"},
+		},
+		{name: "apostrophes inside words",
+			line: "Don’t 'quote' l'homme",
+			want: []string{"don't", "quote", "l'homme"},
+		},
+		{name: "non latin scripts",
+			line: "Привет, мир. 東京",
+			want: []string{"привет", "мир", "東", "京"},
+		},
+		{name: "combining marks are composed",
+			line: "Cafe\u0301 caf\u00e9",
+			want: []string{"caf\u00e9", "caf\u00e9"},
+		},
+		{name: "nfc beyond latin",
+			line: "\u1100\u1161 Vie\u0323\u0302t \u03b1\u0301",
+			want: []string{"\uac00", "vi\u1ec7t", "\u03ac"},
+		},
+		{name: "nfkc and case folding",
+			options: analysisOptions{Tokenizer: tokenizerOptions{Normalization: "nfkc"}},
+			line:    "ＡＢＣ ﬁle STRASSE straße",
+			want:    []string{"abc", "file", "strasse", "strasse"},
+		},
+		{name: "nfkc compatibility forms",
+			options: analysisOptions{Tokenizer: tokenizerOptions{Normalization: "nfkc"}},
+			line:    "x\u00b2 \u2460 \u216b",
+			want:    []string{"x2", "1", "xii"},
+		},
+		{name: "keep case",
+			options: analysisOptions{Tokenizer: tokenizerOptions{KeepCase: true}},
+			line:    "Hello hello",
+			want:    []string{"Hello", "hello"},
+		},
+		{name: "whitespace tokenizer",
+			options: analysisOptions{Tokenizer: tokenizerOptions{Name: "whitespace"}},
+			line:    "Hello, world!",
+			want:    []string{"hello,", "world!"},
+		},
+		{name: "regex tokenizer",
+			options: analysisOptions{Tokenizer: tokenizerOptions{Name: "regex", Pattern: `[a-z]+-[a-z]+|\w+`}},
+			line:    "well-known words",
+			want:    []string{"well-known", "words"},
+		},
+		{name: "stop words and stemming",
+			options: analysisOptions{StopWords: []string{"en"}, Stem: true},
+			line:    "The cats were running and jumping",
+			want:    []string{"cat", "run", "jump"},
+		},
+		{name: "entities are single words",
+			options: analysisOptions{Tokenizer: tokenizerOptions{Entities: true}, StopWords: []string{"en"}, Stem: true},
+			line:    "Mail Bob@Example.com, see https://example.com/Docs_(v2). #GoLang @the_team",
+			want:    []string{"mail", "bob@example.com", "see", "https://example.com/Docs_(v2)", "#golang", "@the_team"},
+		},
+		{name: "unknown stop word list",
+			options: analysisOptions{StopWords: []string{"missing"}},
+			wantErr: true,
+		},
+		{name: "negative",
+			options: analysisOptions{Tokenizer: tokenizerOptions{Name: "regex", Pattern: `(`}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := newAnalyzer(tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("newAnalyzer() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if got := wordTexts(a.words(tt.line)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("analyzer.words() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_countNGrams(t *testing.T) {
+	tests := []struct {
+		name    string
+		options analysisOptions
+		files   []string
+		n       int
+		want    map[string]int
+	}{
+		{name: "sentences and files are boundaries",
+			files: []string{"Hello big world. Big world\nagain", "world again"},
+			n:     2,
+			want:  map[string]int{"hello big": 1, "big world": 2, "world again": 2},
+		},
+		{name: "blank line ends a sentence",
+			files: []string{"one two\n\nthree four"},
+			n:     2,
+			want:  map[string]int{"one two": 1, "three four": 1},
+		},
+		{name: "stop words break n-grams",
+			options: analysisOptions{StopWords: []string{"en"}},
+			files:   []string{"cat on the mat sat quietly"},
+			n:       2,
+			want:    map[string]int{"mat sat": 1, "sat quietly": 1},
+		},
+		{name: "trigrams",
+			files: []string{"a b c d? e f"},
+			n:     3,
+			want:  map[string]int{"a b c": 1, "b c d": 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := newAnalyzer(tt.options)
+			if err != nil {
+				t.Fatalf("newAnalyzer() error = %v", err)
+			}
+			analyses := []*fileAnalysis{}
+			for _, content := range tt.files {
+				analysis, _ := analyzeReader("", strings.NewReader(content), a)
+				analyses = append(analyses, analysis)
+			}
+			if got := countNGrams(analyses, tt.n); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("countNGrams() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_countCoOccurrences(t *testing.T) {
+	tests := []struct {
+		name      string
+		files     []string
+		window    int
+		want      map[wordPair]int
+		wantTotal int
+	}{
+		{name: "window",
+			files:     []string{"a b c a"},
+			window:    2,
+			want:      map[wordPair]int{{"a", "b"}: 2, {"a", "c"}: 2, {"b", "c"}: 1},
+			wantTotal: 10,
+		},
+		{name: "sentences and files are boundaries",
+			files:     []string{"a b. c", "d"},
+			window:    5,
+			want:      map[wordPair]int{{"a", "b"}: 1},
+			wantTotal: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, _ := newAnalyzer(analysisOptions{})
+			analyses := []*fileAnalysis{}
+			for _, content := range tt.files {
+				analysis, _ := analyzeReader("", strings.NewReader(content), a)
+				analyses = append(analyses, analysis)
+			}
+			got := countCoOccurrences(analyses, tt.window)
+			if !reflect.DeepEqual(got.pairs, tt.want) || got.total != tt.wantTotal {
+				t.Errorf("countCoOccurrences() = %v %v, want %v %v", got.pairs, got.total, tt.want, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func Test_findEntities(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []entity
+	}{
+		{name: "all types",
+			text: "ping @ann at ann@mail.org or #go (www.go.dev)",
+			want: []entity{
+				{Type: entityMention, Text: "@ann", Start: 5, End: 9},
+				{Type: entityEmail, Text: "ann@mail.org", Start: 13, End: 25},
+				{Type: entityHashtag, Text: "#go", Start: 29, End: 32},
+				{Type: entityURL, Text: "www.go.dev", Start: 34, End: 44},
+			},
+		},
+		{name: "not starting a word",
+			text: "C# and a@b and #1",
+			want: []entity{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := findEntities(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findEntities() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
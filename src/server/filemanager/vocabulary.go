@@ -0,0 +1,247 @@
+package filemanager
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// defaultVocabularyLimit is the number of words returned by default
+// maxEditDistance is the largest accepted edit distance of fuzzy matches
+const (
+	defaultVocabularyLimit = 10
+	maxEditDistance        = 3
+)
+
+// vocabularyResponse is representing completions and fuzzy matches of a word
+type vocabularyResponse struct {
+	Query       string           `json:"query"`
+	Completions []vocabularyWord `json:"completions"`
+	Fuzzy       []vocabularyWord `json:"fuzzy"`
+}
+
+// vocabularyWord is representing a word of the vocabulary with its count
+// and its edit distance from the query
+type vocabularyWord struct {
+	Word     string `json:"word"`
+	Count    int    `json:"count"`
+	Distance int    `json:"distance"`
+}
+
+// trieNode is a node of a trie of all the indexed words
+// word and count are set on nodes where a word ends, the trie is kept in
+// the index and updated with it
+type trieNode struct {
+	children map[rune]*trieNode
+	word     string
+	count    int
+}
+
+// Vocabulary is returning the indexed words starting with a query (q) ranked
+// by frequency and the words within an edit distance (distance, default 1)
+// of the query, algorithm is levenshtein (default) or damerau which
+// counts swapping two adjacent letters as one edit
+func (fm *fileManager) Vocabulary(r *http.Request) (interface{}, error) {
+	values := r.URL.Query()
+	query := normalizeWord(strings.TrimSpace(values.Get("q")), tokenizerOptions{})
+	if query == "" {
+		return nil, &statusError{code: http.StatusBadRequest, err: errors.New("vocabulary query is empty")}
+	}
+
+	limit, err := queryInt(values.Get("limit"), defaultVocabularyLimit)
+	if err != nil {
+		return nil, err
+	}
+	distance, err := queryInt(values.Get("distance"), 1)
+	if err != nil {
+		return nil, err
+	}
+	if distance > maxEditDistance {
+		return nil, &statusError{code: http.StatusBadRequest, err: fmt.Errorf("invalid distance %v, distance has to be at most %v", distance, maxEditDistance)}
+	}
+
+	damerau := false
+	switch values.Get("algorithm") {
+	case "", "levenshtein":
+	case "damerau":
+		damerau = true
+	default:
+		return nil, &statusError{code: http.StatusBadRequest, err: fmt.Errorf("invalid edit distance algorithm %v", values.Get("algorithm"))}
+	}
+
+	if err := fm.index.ensureBuilt(); err != nil {
+		return nil, err
+	}
+	fm.index.mu.RLock()
+	completions := fm.index.trie.completions(query)
+	fuzzy := fm.index.trie.fuzzy(query, distance, damerau)
+	fm.index.mu.RUnlock()
+
+	sort.Slice(completions, func(i, j int) bool {
+		if completions[i].Count != completions[j].Count {
+			return completions[i].Count > completions[j].Count
+		}
+		return completions[i].Word < completions[j].Word
+	})
+
+	sort.Slice(fuzzy, func(i, j int) bool {
+		if fuzzy[i].Distance != fuzzy[j].Distance {
+			return fuzzy[i].Distance < fuzzy[j].Distance
+		}
+		if fuzzy[i].Count != fuzzy[j].Count {
+			return fuzzy[i].Count > fuzzy[j].Count
+		}
+		return fuzzy[i].Word < fuzzy[j].Word
+	})
+
+	if len(completions) > limit {
+		completions = completions[:limit]
+	}
+	if len(fuzzy) > limit {
+		fuzzy = fuzzy[:limit]
+	}
+	return &vocabularyResponse{
+		Query:       query,
+		Completions: completions,
+		Fuzzy:       fuzzy,
+	}, nil
+}
+
+// newTrieNode is creating an empty trie node
+func newTrieNode() *trieNode {
+	return &trieNode{children: make(map[rune]*trieNode)}
+}
+
+// insert is adding a word with its count
+func (t *trieNode) insert(word string, count int) {
+	node := t
+	for _, r := range word {
+		child, ok := node.children[r]
+		if !ok {
+			child = newTrieNode()
+			node.children[r] = child
+		}
+		node = child
+	}
+	node.word = word
+	node.count += count
+}
+
+// remove is subtracting count from a word, the word is dropped when its
+// count reaches 0 together with the nodes which lead to no other word
+func (t *trieNode) remove(word string, count int) {
+	path := []*trieNode{t}
+	runes := []rune(word)
+	for _, r := range runes {
+		child := path[len(path)-1].children[r]
+		if child == nil {
+			return
+		}
+		path = append(path, child)
+	}
+
+	node := path[len(path)-1]
+	node.count -= count
+	if node.count > 0 {
+		return
+	}
+	node.word = ""
+	node.count = 0
+	for i := len(runes) - 1; i >= 0; i-- {
+		node := path[i+1]
+		if node.word != "" || len(node.children) > 0 {
+			return
+		}
+		delete(path[i].children, runes[i])
+	}
+}
+
+// completions is returning all the words starting with prefix
+func (t *trieNode) completions(prefix string) []vocabularyWord {
+	node := t
+	for _, r := range prefix {
+		node = node.children[r]
+		if node == nil {
+			return []vocabularyWord{}
+		}
+	}
+
+	words := []vocabularyWord{}
+	var walk func(node *trieNode)
+	walk = func(node *trieNode) {
+		if node.word != "" {
+			words = append(words, vocabularyWord{Word: node.word, Count: node.count})
+		}
+		for _, child := range node.children {
+			walk(child)
+		}
+	}
+	walk(node)
+	return words
+}
+
+// fuzzy is returning all the words within maxDistance edits of target
+// the edit distance table is computed row by row while walking the trie
+// so branches which can not get close enough are skipped
+func (t *trieNode) fuzzy(target string, maxDistance int, damerau bool) []vocabularyWord {
+	runes := []rune(target)
+	firstRow := make([]int, len(runes)+1)
+	for i := range firstRow {
+		firstRow[i] = i
+	}
+
+	words := []vocabularyWord{}
+	var walk func(node *trieNode, r, prevRune rune, prevPrevRow, prevRow []int)
+	walk = func(node *trieNode, r, prevRune rune, prevPrevRow, prevRow []int) {
+		row := make([]int, len(runes)+1)
+		row[0] = prevRow[0] + 1
+		rowMin := row[0]
+		for i := 1; i <= len(runes); i++ {
+			cost := 1
+			if runes[i-1] == r {
+				cost = 0
+			}
+			row[i] = minInt(row[i-1]+1, minInt(prevRow[i]+1, prevRow[i-1]+cost))
+			if damerau && prevPrevRow != nil && i > 1 && runes[i-1] == prevRune && runes[i-2] == r {
+				row[i] = minInt(row[i], prevPrevRow[i-2]+1)
+			}
+			rowMin = minInt(rowMin, row[i])
+		}
+
+		if node.word != "" && row[len(runes)] <= maxDistance {
+			words = append(words, vocabularyWord{Word: node.word, Count: node.count, Distance: row[len(runes)]})
+		}
+
+		// a transposition can still reach back one row
+		if rowMin > maxDistance && (!damerau || minSlice(prevRow)+1 > maxDistance) {
+			return
+		}
+		for childRune, child := range node.children {
+			walk(child, childRune, r, prevRow, row)
+		}
+	}
+
+	for r, child := range t.children {
+		walk(child, r, 0, nil, firstRow)
+	}
+	return words
+}
+
+// minInt is returning the smaller of two integers
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// minSlice is returning the smallest integer of a non empty slice
+func minSlice(values []int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		m = minInt(m, v)
+	}
+	return m
+}
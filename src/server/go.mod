@@ -2,4 +2,7 @@ module server
 
 go 1.15
 
-require github.com/gorilla/mux v1.8.0
+require (
+	github.com/gorilla/mux v1.8.0
+	golang.org/x/text v0.3.6
+)
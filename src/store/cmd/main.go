@@ -26,6 +26,29 @@ const (
 	RM        string = "rm"
 	WC        string = "wc"
 	FREQWORDS string = "freq-words"
+	STOPWORDS string = "stopwords"
+	NGRAMS    string = "ngrams"
+	WORDFILES string = "word-files"
+	KEYWORDS  string = "keywords"
+	SIMILAR   string = "similar"
+	SEARCH    string = "search"
+	GREP      string = "grep"
+	VOCAB     string = "vocab"
+	KWIC      string = "kwic"
+	COLLOCS   string = "collocations"
+	COMPARE   string = "compare"
+	ANALYZE   string = "analyze"
+	ENTITIES  string = "entities"
+	GET       string = "get"
+	INFO      string = "info"
+	DESCRIBE  string = "describe"
+	QUERY     string = "query"
+	APPEND    string = "append"
+	HEAD      string = "head"
+	TAIL      string = "tail"
+	DIFF      string = "diff"
+	PII       string = "pii"
+	POLICY    string = "policy"
 )
 
 const (
@@ -49,7 +72,53 @@ func main() {
 		storeManager.WordCounts()
 	case "freq-words":
 		storeManager.WordFrequency()
+	case STOPWORDS:
+		storeManager.StopWords()
+	case NGRAMS:
+		storeManager.NGrams()
+	case WORDFILES:
+		storeManager.WordFiles()
+	case KEYWORDS:
+		storeManager.Keywords()
+	case SIMILAR:
+		storeManager.Similar()
+	case SEARCH:
+		storeManager.Search()
+	case GREP:
+		storeManager.Grep()
+	case VOCAB:
+		storeManager.Vocabulary()
+	case KWIC:
+		storeManager.KWIC()
+	case COLLOCS:
+		storeManager.Collocations()
+	case COMPARE:
+		storeManager.Compare()
+	case ANALYZE:
+		storeManager.Analyze()
+	case ENTITIES:
+		storeManager.Entities()
+	case GET:
+		storeManager.DownloadFile()
+	case INFO:
+		storeManager.FileInfo()
+	case DESCRIBE:
+		storeManager.Describe()
+	case QUERY:
+		storeManager.Query()
+	case APPEND:
+		storeManager.Append()
+	case HEAD:
+		storeManager.Head()
+	case TAIL:
+		storeManager.Tail()
+	case DIFF:
+		storeManager.Diff()
+	case PII:
+		storeManager.PII()
+	case POLICY:
+		storeManager.Policy()
 	default:
-		fmt.Println(fmt.Errorf("command \"%s\" is not valid", storeManager.Command))
+		fmt.Println(fmt.Errorf("command \"%s\" is not valid", storeManager.Command()))
 	}
 }
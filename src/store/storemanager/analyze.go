@@ -0,0 +1,117 @@
+package storemanager
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// readabilityRequest is request when readability of files is fetched
+type readabilityRequest struct {
+	analysisOptions
+}
+
+// lengthBucket is the number of sentences with From to To words
+type lengthBucket struct {
+	From  int `json:"from"`
+	To    int `json:"to"`
+	Count int `json:"count"`
+}
+
+// sentenceLengths is the distribution of the sentence lengths in words
+type sentenceLengths struct {
+	Min     int            `json:"min"`
+	Max     int            `json:"max"`
+	Mean    float64        `json:"mean"`
+	Median  float64        `json:"median"`
+	Buckets []lengthBucket `json:"buckets"`
+}
+
+// readabilityStats is sentence statistics with readability scores
+type readabilityStats struct {
+	Sentences          int             `json:"sentences"`
+	Words              int             `json:"words"`
+	UniqueWords        int             `json:"unique_words"`
+	Syllables          int             `json:"syllables"`
+	ComplexWords       int             `json:"complex_words"`
+	FleschReadingEase  float64         `json:"flesch_reading_ease"`
+	FleschKincaidGrade float64         `json:"flesch_kincaid_grade"`
+	GunningFog         float64         `json:"gunning_fog"`
+	TypeTokenRatio     float64         `json:"type_token_ratio"`
+	SentenceLengths    sentenceLengths `json:"sentence_lengths"`
+}
+
+// fileReadability is readability of a single file
+type fileReadability struct {
+	Name string `json:"name"`
+	readabilityStats
+}
+
+// readabilityResponse is response when readability of files is fetched
+type readabilityResponse struct {
+	Files []fileReadability `json:"files"`
+	Total readabilityStats  `json:"total"`
+}
+
+func (st *store) Analyze() {
+	readabilityRequest := &readabilityRequest{}
+	var distribution bool
+
+	flags := flag.NewFlagSet(st.command, flag.ExitOnError)
+	flags.BoolVar(&distribution, "lengths", false, "print the sentence length distribution")
+	options := analysisFlags(flags)
+	options.filterArgs(parseFlags(flags, st.options))
+	readabilityRequest.analysisOptions = *options
+
+	bodyBytes, err := st.createAndExecuteHTTPRequest(http.MethodGet, "analyze", readabilityRequest)
+	if err != nil {
+		fmt.Printf("error occured while analysing the files : %v", err)
+		os.Exit(1)
+	}
+
+	readabilityResponse := &readabilityResponse{}
+	if err := json.Unmarshal(bodyBytes, readabilityResponse); err != nil {
+		fmt.Println("error while reading the response from server")
+		os.Exit(1)
+	}
+
+	if len(readabilityResponse.Files) == 0 {
+		fmt.Println("no files found on server")
+		return
+	}
+
+	rows := readabilityResponse.Files
+	if len(rows) > 1 {
+		rows = append(rows, fileReadability{Name: "total", readabilityStats: readabilityResponse.Total})
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SENTENCES\tWORDS\tSYLLABLES\tWORDS/SENTENCE\tFLESCH\tF-K GRADE\tFOG\tTTR\tFILE")
+	for _, row := range rows {
+		fmt.Fprintf(w, "%v\t%v\t%v\t%.1f\t%.1f\t%.1f\t%.1f\t%.3f\t%v\n", row.Sentences, row.Words, row.Syllables,
+			row.SentenceLengths.Mean, row.FleschReadingEase, row.FleschKincaidGrade, row.GunningFog, row.TypeTokenRatio, row.Name)
+	}
+	w.Flush()
+
+	if !distribution {
+		return
+	}
+	for _, row := range rows {
+		lengths := row.SentenceLengths
+		fmt.Printf("\n%v: min %v, max %v, median %v words per sentence\n", row.Name, lengths.Min, lengths.Max, lengths.Median)
+		for _, bucket := range lengths.Buckets {
+			fmt.Printf("%4d-%-4d %6d %v\n", bucket.From, bucket.To, bucket.Count, strings.Repeat("#", histogramWidth(bucket.Count, row.Sentences)))
+		}
+	}
+}
+
+// histogramWidth is the length of a histogram bar of count out of total
+func histogramWidth(count, total int) int {
+	if total == 0 {
+		return 0
+	}
+	return (count*40 + total - 1) / total
+}
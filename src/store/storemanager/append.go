@@ -0,0 +1,89 @@
+package storemanager
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// appendChunkSize is the size of the stdin buffer, the lines in it are
+// appended in a single request
+const appendChunkSize = 64 << 10
+
+// appendRequest is request when text is appended to a stored file
+type appendRequest struct {
+	Name    string `json:"name"`
+	Content []byte `json:"content"`
+}
+
+// appendResponse is where the text was appended and what was flagged in it
+type appendResponse struct {
+	Offset  int64             `json:"offset"`
+	Size    int64             `json:"size"`
+	PII     []json.RawMessage `json:"pii"`
+	Secrets []json.RawMessage `json:"secrets"`
+}
+
+// Append is appending a line to a stored file, the file is created when it
+// does not exist, without text the lines of stdin are appended as they are
+// read so that a log can be piped into the store
+// store append <name> [text...]
+func (st *store) Append() {
+	if len(st.options) == 0 {
+		fmt.Println("file name is required")
+		os.Exit(1)
+	}
+	name := st.options[0]
+
+	if len(st.options) > 1 {
+		st.appendText(name, strings.Join(st.options[1:], " ")+"\n")
+		return
+	}
+	rdr := bufio.NewReaderSize(os.Stdin, appendChunkSize)
+	var chunk strings.Builder
+	for {
+		line, err := rdr.ReadString('\n')
+		if line != "" {
+			if !strings.HasSuffix(line, "\n") {
+				line += "\n"
+			}
+			chunk.WriteString(line)
+		}
+		// lines which were read together are appended together, a line is
+		// not held back waiting for the next one
+		if chunk.Len() > 0 && (err != nil || rdr.Buffered() == 0 || chunk.Len() >= appendChunkSize) {
+			st.appendText(name, chunk.String())
+			chunk.Reset()
+		}
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			fmt.Printf("error occured while reading stdin : %v", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// appendText is appending text to a stored file and printing the findings
+func (st *store) appendText(name, text string) {
+	bodyBytes, err := st.createAndExecuteHTTPRequest(http.MethodPost, "appendfile", &appendRequest{Name: name, Content: []byte(text)})
+	if err != nil {
+		fmt.Printf("error occured while appending to the file : %v", err)
+		os.Exit(1)
+	}
+
+	appendResponse := &appendResponse{}
+	if err := json.Unmarshal(bodyBytes, appendResponse); err != nil {
+		fmt.Println("error while reading the response from server")
+		os.Exit(1)
+	}
+	if len(appendResponse.PII) > 0 || len(appendResponse.Secrets) > 0 {
+		fmt.Fprintf(os.Stderr, "appended text at offset %v of %v was flagged, %v pii and %v secrets reports\n",
+			appendResponse.Offset, name, len(appendResponse.PII), len(appendResponse.Secrets))
+	}
+}
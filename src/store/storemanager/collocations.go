@@ -0,0 +1,82 @@
+package storemanager
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// collocationRequest is request when words co-occurring are fetched
+type collocationRequest struct {
+	analysisOptions
+	Word     string `json:"word"`
+	Window   int    `json:"window"`
+	Measure  string `json:"measure"`
+	MinCount int    `json:"min_count"`
+	Limit    int    `json:"limit"`
+}
+
+// collocation is two words co-occurring with their score
+type collocation struct {
+	First  string  `json:"first"`
+	Second string  `json:"second"`
+	Count  int     `json:"count"`
+	Score  float64 `json:"score"`
+}
+
+// collocationResponse is response when words co-occurring are fetched
+type collocationResponse struct {
+	Word         string        `json:"word"`
+	Measure      string        `json:"measure"`
+	Window       int           `json:"window"`
+	Total        int           `json:"total"`
+	Collocations []collocation `json:"collocations"`
+}
+
+func (st *store) Collocations() {
+	collocationRequest := &collocationRequest{}
+
+	flags := flag.NewFlagSet(st.command, flag.ExitOnError)
+	flags.StringVar(&collocationRequest.Word, "word", "", "only pairs containing the word, whole store when empty")
+	flags.IntVar(&collocationRequest.Window, "window", 5, "largest distance in words between two words")
+	flags.StringVar(&collocationRequest.Measure, "measure", "pmi", "ranking of the pairs pmi|llr")
+	flags.IntVar(&collocationRequest.MinCount, "min-count", 1, "minimum number of co-occurrences of a pair")
+	flags.IntVar(&collocationRequest.Limit, "limit", 10, "number of pairs to return")
+	options := analysisFlags(flags)
+	options.filterArgs(parseFlags(flags, st.options))
+	collocationRequest.analysisOptions = *options
+
+	if collocationRequest.Measure != "pmi" && collocationRequest.Measure != "llr" {
+		fmt.Printf("invalid measure provided %v", collocationRequest.Measure)
+		os.Exit(1)
+	}
+
+	bodyBytes, err := st.createAndExecuteHTTPRequest(http.MethodGet, "collocations", collocationRequest)
+	if err != nil {
+		fmt.Printf("error occured while getting the collocations : %v", err)
+		os.Exit(1)
+	}
+
+	collocationResponse := &collocationResponse{}
+	if err := json.Unmarshal(bodyBytes, collocationResponse); err != nil {
+		fmt.Println("error while reading the response from server")
+		os.Exit(1)
+	}
+
+	if len(collocationResponse.Collocations) == 0 {
+		fmt.Println("no collocations found on server")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "%v\tCOUNT\tWORDS\n", strings.ToUpper(collocationResponse.Measure))
+	for _, c := range collocationResponse.Collocations {
+		fmt.Fprintf(w, "%.3f\t%v\t%v %v\n", c.Score, c.Count, c.First, c.Second)
+	}
+	w.Flush()
+	fmt.Printf("%v of %v pairs within %v words\n", len(collocationResponse.Collocations), collocationResponse.Total, collocationResponse.Window)
+}
@@ -0,0 +1,111 @@
+package storemanager
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// compareRequest is request when two sets of files are compared
+type compareRequest struct {
+	analysisOptions
+	Target    fileFilter `json:"target"`
+	Reference fileFilter `json:"reference"`
+	Limit     int        `json:"limit"`
+}
+
+// keyWord is a word with its counts and frequencies (per million words) in both the sets
+type keyWord struct {
+	Word               string  `json:"word"`
+	TargetCount        int     `json:"target_count"`
+	ReferenceCount     int     `json:"reference_count"`
+	TargetFrequency    float64 `json:"target_frequency"`
+	ReferenceFrequency float64 `json:"reference_frequency"`
+	LogLikelihood      float64 `json:"log_likelihood"`
+}
+
+// corpusSet is the files and the number of words of a set
+type corpusSet struct {
+	Files []string `json:"files"`
+	Words int      `json:"words"`
+}
+
+// compareResponse is response when two sets of files are compared
+type compareResponse struct {
+	Target    corpusSet `json:"target"`
+	Reference corpusSet `json:"reference"`
+	Overused  []keyWord `json:"overused"`
+	Underused []keyWord `json:"underused"`
+}
+
+func (st *store) Compare() {
+	compareRequest := &compareRequest{}
+	var target, reference stringList
+
+	flags := flag.NewFlagSet(st.command, flag.ExitOnError)
+	flags.Var(&target, "target", "comma separated files, globs or a directory/ of the target set")
+	flags.Var(&reference, "reference", "comma separated files, globs or a directory/ of the reference set (rest of the files when empty)")
+	flags.IntVar(&compareRequest.Limit, "limit", 10, "number of words over and under represented")
+	options := analysisFlags(flags)
+	options.filterArgs(parseFlags(flags, st.options))
+	compareRequest.analysisOptions = *options
+
+	if len(target) == 0 {
+		fmt.Println("no target set is specified")
+		os.Exit(1)
+	}
+	compareRequest.Target = setFilter(target)
+	compareRequest.Reference = setFilter(reference)
+
+	bodyBytes, err := st.createAndExecuteHTTPRequest(http.MethodGet, "compare", compareRequest)
+	if err != nil {
+		fmt.Printf("error occured while comparing the files : %v", err)
+		os.Exit(1)
+	}
+
+	compareResponse := &compareResponse{}
+	if err := json.Unmarshal(bodyBytes, compareResponse); err != nil {
+		fmt.Println("error while reading the response from server")
+		os.Exit(1)
+	}
+
+	fmt.Printf("target: %v files, %v words\n", len(compareResponse.Target.Files), compareResponse.Target.Words)
+	fmt.Printf("reference: %v files, %v words\n", len(compareResponse.Reference.Files), compareResponse.Reference.Words)
+	printKeyWords("OVERUSED", compareResponse.Overused)
+	printKeyWords("UNDERUSED", compareResponse.Underused)
+}
+
+// setFilter is selecting the files of a set, a value ending with / is a directory
+func setFilter(values []string) fileFilter {
+	ff := fileFilter{}
+	args := []string{}
+	for _, value := range values {
+		if strings.HasSuffix(value, "/") && ff.Prefix == "" {
+			ff.Prefix = value
+			continue
+		}
+		args = append(args, value)
+	}
+	ff.filterArgs(args)
+	return ff
+}
+
+// printKeyWords is printing the words with log-likelihood and both the frequencies
+func printKeyWords(title string, words []keyWord) {
+	fmt.Println()
+	if len(words) == 0 {
+		fmt.Printf("no %v words\n", strings.ToLower(title))
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "%v\tLL\tTARGET\tPER MILLION\tREFERENCE\tPER MILLION\n", title)
+	for _, word := range words {
+		fmt.Fprintf(w, "%v\t%.2f\t%v\t%.1f\t%v\t%.1f\n", word.Word, word.LogLikelihood,
+			word.TargetCount, word.TargetFrequency, word.ReferenceCount, word.ReferenceFrequency)
+	}
+	w.Flush()
+}
@@ -0,0 +1,122 @@
+package storemanager
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// describeRequest is request when the columns of a csv file are described
+type describeRequest struct {
+	Name      string `json:"name"`
+	Delimiter string `json:"delimiter,omitempty"`
+	Header    *bool  `json:"header,omitempty"`
+	Top       int    `json:"top,omitempty"`
+}
+
+// valueCount is a value of a column and the rows it is in
+type valueCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// columnStats is the type and statistics of a column
+type columnStats struct {
+	Name     string       `json:"name"`
+	Type     string       `json:"type"`
+	Nulls    int          `json:"nulls"`
+	Distinct int          `json:"distinct"`
+	Min      *float64     `json:"min"`
+	Max      *float64     `json:"max"`
+	Mean     *float64     `json:"mean"`
+	Top      []valueCount `json:"top"`
+}
+
+// describeResponse is response when the columns of a csv file are described
+type describeResponse struct {
+	Name       string        `json:"name"`
+	Delimiter  string        `json:"delimiter"`
+	Header     bool          `json:"header"`
+	Rows       int           `json:"rows"`
+	RaggedRows int           `json:"ragged_rows"`
+	Columns    []columnStats `json:"columns"`
+}
+
+// Describe is printing the column types and statistics of a csv file
+// store describe [--delimiter ';'] [--header yes|no] [--top 5] <file>
+func (st *store) Describe() {
+	describeRequest := &describeRequest{}
+	var header string
+	flags := flag.NewFlagSet(st.command, flag.ExitOnError)
+	flags.StringVar(&describeRequest.Delimiter, "delimiter", "", "field delimiter (detected when not given)")
+	flags.StringVar(&header, "header", "", "first row is a header yes|no (detected when not given)")
+	flags.IntVar(&describeRequest.Top, "top", 5, "most frequent values of text columns")
+	args := parseFlags(flags, st.options)
+	if len(args) != 1 {
+		fmt.Println("a single file name is required")
+		os.Exit(1)
+	}
+	describeRequest.Name = args[0]
+	switch header {
+	case "":
+	case "yes", "no":
+		hasHeader := header == "yes"
+		describeRequest.Header = &hasHeader
+	default:
+		fmt.Printf("header %v is not valid, header has to be yes or no", header)
+		os.Exit(1)
+	}
+	if describeRequest.Delimiter == `\t` {
+		describeRequest.Delimiter = "\t"
+	}
+
+	bodyBytes, err := st.createAndExecuteHTTPRequest(http.MethodGet, "describe", describeRequest)
+	if err != nil {
+		fmt.Printf("error occured while describing the file : %v", err)
+		os.Exit(1)
+	}
+
+	describeResponse := &describeResponse{}
+	if err := json.Unmarshal(bodyBytes, describeResponse); err != nil {
+		fmt.Println("error while reading the response from server")
+		os.Exit(1)
+	}
+
+	headerText := "no header"
+	if describeResponse.Header {
+		headerText = "header"
+	}
+	fmt.Printf("%v: %v rows, %v columns, delimiter %q, %v\n", describeResponse.Name, describeResponse.Rows,
+		len(describeResponse.Columns), describeResponse.Delimiter, headerText)
+	if describeResponse.RaggedRows > 0 {
+		fmt.Printf("warning: %v rows do not have %v fields\n", describeResponse.RaggedRows, len(describeResponse.Columns))
+	}
+	if len(describeResponse.Columns) == 0 {
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "COLUMN\tTYPE\tNULLS\tDISTINCT\tMIN\tMAX\tMEAN\tTOP VALUES")
+	for _, column := range describeResponse.Columns {
+		top := make([]string, 0, len(column.Top))
+		for _, value := range column.Top {
+			top = append(top, fmt.Sprintf("%v (%v)", value.Value, value.Count))
+		}
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\n", column.Name, column.Type, column.Nulls, column.Distinct,
+			numberText(column.Min), numberText(column.Max), numberText(column.Mean), strings.Join(top, ", "))
+	}
+	w.Flush()
+}
+
+// numberText is printing a statistic which is only given for numeric columns
+func numberText(f *float64) string {
+	if f == nil {
+		return "-"
+	}
+	return strconv.FormatFloat(*f, 'g', 6, 64)
+}
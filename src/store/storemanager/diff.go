@@ -0,0 +1,96 @@
+package storemanager
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+)
+
+// diffRequest is request when a stored file is compared with another
+// stored file or with the content of a local file
+type diffRequest struct {
+	Name    string `json:"name"`
+	Other   string `json:"other,omitempty"`
+	Content []byte `json:"content,omitempty"`
+	Label   string `json:"label,omitempty"`
+	Context *int   `json:"context,omitempty"`
+}
+
+// diffStats is the number of added and removed lines
+type diffStats struct {
+	Added   int `json:"added"`
+	Removed int `json:"removed"`
+	Hunks   int `json:"hunks"`
+}
+
+// diffResponse is the unified diff, the hunks and the stats of two files
+type diffResponse struct {
+	Unified string            `json:"unified"`
+	Hunks   []json.RawMessage `json:"hunks"`
+	Stats   diffStats         `json:"stats"`
+}
+
+// Diff is printing the unified diff of a stored file and a local file or
+// another stored file, a local file with the name of the stored file is
+// compared when only one file is given
+// it exits with 1 when the files differ like diff
+// store diff [-U 3] [--stat] [--json] <remote> [<local>|<remote2>]
+func (st *store) Diff() {
+	var context int
+	var stat, hunks bool
+	flags := flag.NewFlagSet(st.command, flag.ExitOnError)
+	flags.IntVar(&context, "U", 3, "number of unchanged lines around the changes")
+	flags.BoolVar(&stat, "stat", false, "print only the number of added and removed lines")
+	flags.BoolVar(&hunks, "json", false, "print the hunks as json")
+	args := parseFlags(flags, st.options)
+	if len(args) == 0 || len(args) > 2 {
+		fmt.Println("a stored file and a local or stored file to compare it with are required")
+		os.Exit(2)
+	}
+
+	diffRequest := &diffRequest{Name: args[0], Context: &context}
+	other := args[0]
+	if len(args) == 2 {
+		other = args[1]
+	}
+	if _, err := os.Stat(other); err == nil {
+		content, err := ioutil.ReadFile(other)
+		if err != nil {
+			fmt.Printf("error occured while reading the local file : %v", err)
+			os.Exit(2)
+		}
+		diffRequest.Content, diffRequest.Label = content, other
+	} else if len(args) == 2 {
+		diffRequest.Other = other
+	} else {
+		fmt.Printf("local file %v does not exist", other)
+		os.Exit(2)
+	}
+
+	bodyBytes, err := st.createAndExecuteHTTPRequest(http.MethodGet, "diff", diffRequest)
+	if err != nil {
+		fmt.Printf("error occured while comparing the files : %v", err)
+		os.Exit(2)
+	}
+	diffResponse := &diffResponse{}
+	if err := json.Unmarshal(bodyBytes, diffResponse); err != nil {
+		fmt.Println("error while reading the response from server")
+		os.Exit(2)
+	}
+
+	switch {
+	case stat:
+		fmt.Printf("%v lines added, %v lines removed in %v hunks\n", diffResponse.Stats.Added, diffResponse.Stats.Removed, diffResponse.Stats.Hunks)
+	case hunks:
+		out, _ := json.MarshalIndent(diffResponse.Hunks, "", "  ")
+		fmt.Println(string(out))
+	default:
+		fmt.Print(diffResponse.Unified)
+	}
+	if len(diffResponse.Hunks) > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,52 @@
+package storemanager
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+)
+
+// downloadRequest is request when a file is downloaded
+type downloadRequest struct {
+	Name   string `json:"name"`
+	Redact bool   `json:"redact,omitempty"`
+}
+
+// DownloadFile is writing a stored file to a local file or to stdout
+// store get [--redact] <name> [<local file>]
+func (st *store) DownloadFile() {
+	downloadRequest := &downloadRequest{}
+	flags := flag.NewFlagSet(st.command, flag.ExitOnError)
+	flags.BoolVar(&downloadRequest.Redact, "redact", false, "replace pii found by the server rules")
+	args := parseFlags(flags, st.options)
+	if len(args) == 0 || len(args) > 2 {
+		fmt.Println("file name and an optional local file are required")
+		os.Exit(1)
+	}
+	downloadRequest.Name = args[0]
+
+	bodyBytes, err := st.createAndExecuteHTTPRequest(http.MethodGet, "downloadfile", downloadRequest)
+	if err != nil {
+		fmt.Printf("error occured while downloading the file : %v", err)
+		os.Exit(1)
+	}
+
+	downloaded := &file{}
+	if err := json.Unmarshal(bodyBytes, downloaded); err != nil {
+		fmt.Println("error while reading the response from server")
+		os.Exit(1)
+	}
+
+	if len(args) == 1 {
+		os.Stdout.Write(downloaded.Content)
+		return
+	}
+	if err := ioutil.WriteFile(args[1], downloaded.Content, 0644); err != nil {
+		fmt.Printf("error occured while writing the file %v", err)
+		os.Exit(1)
+	}
+	fmt.Println("file downloaded successfully")
+}
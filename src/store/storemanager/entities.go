@@ -0,0 +1,88 @@
+package storemanager
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// entitiesRequest is request when urls, emails, mentions and hashtags are extracted
+type entitiesRequest struct {
+	fileFilter
+	Types         stringList `json:"types,omitempty"`
+	Limit         int        `json:"limit"`
+	IncludeBinary bool       `json:"include_binary,omitempty"`
+}
+
+// entityFile is the number of times an entity appears in a file
+type entityFile struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// entityCount is an entity with its frequency and files
+type entityCount struct {
+	Text  string       `json:"text"`
+	Count int          `json:"count"`
+	Files []entityFile `json:"files"`
+}
+
+// entityGroup is the entities of a type
+type entityGroup struct {
+	Type     string        `json:"type"`
+	Total    int           `json:"total"`
+	Unique   int           `json:"unique"`
+	Entities []entityCount `json:"entities"`
+}
+
+// entitiesResponse is response when urls, emails, mentions and hashtags are extracted
+type entitiesResponse struct {
+	Types []entityGroup `json:"types"`
+}
+
+func (st *store) Entities() {
+	entitiesRequest := &entitiesRequest{}
+
+	flags := flag.NewFlagSet(st.command, flag.ExitOnError)
+	flags.Var(&entitiesRequest.Types, "type", "comma separated entity types url,email,mention,hashtag (all when not given)")
+	flags.IntVar(&entitiesRequest.Limit, "limit", 10, "number of entities per type (all when 0)")
+	flags.StringVar(&entitiesRequest.Prefix, "prefix", "", "only files with the name prefix (directory)")
+	flags.BoolVar(&entitiesRequest.IncludeBinary, "include-binary", false, "read binary files as well")
+	entitiesRequest.filterArgs(parseFlags(flags, st.options))
+
+	bodyBytes, err := st.createAndExecuteHTTPRequest(http.MethodGet, "entities", entitiesRequest)
+	if err != nil {
+		fmt.Printf("error occured while extracting the entities : %v", err)
+		os.Exit(1)
+	}
+
+	entitiesResponse := &entitiesResponse{}
+	if err := json.Unmarshal(bodyBytes, entitiesResponse); err != nil {
+		fmt.Println("error while reading the response from server")
+		os.Exit(1)
+	}
+
+	for i, group := range entitiesResponse.Types {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%v: %v occurrences of %v %vs\n", group.Type, group.Total, group.Unique, group.Type)
+		if len(group.Entities) == 0 {
+			continue
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "COUNT\tENTITY\tFILES")
+		for _, entity := range group.Entities {
+			files := make([]string, 0, len(entity.Files))
+			for _, file := range entity.Files {
+				files = append(files, fmt.Sprintf("%v(%v)", file.Name, file.Count))
+			}
+			fmt.Fprintf(w, "%v\t%v\t%v\n", entity.Count, entity.Text, strings.Join(files, " "))
+		}
+		w.Flush()
+	}
+}
@@ -0,0 +1,20 @@
+package storemanager
+
+import "strings"
+
+// stringList is a flag holding comma separated values
+// the flag can be repeated to add more values
+type stringList []string
+
+func (sl *stringList) String() string {
+	return strings.Join(*sl, ",")
+}
+
+func (sl *stringList) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*sl = append(*sl, v)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package storemanager
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// grepRequest is request when files are grepped
+type grepRequest struct {
+	fileFilter
+	Pattern       string `json:"pattern"`
+	IgnoreCase    bool   `json:"ignore_case"`
+	Before        int    `json:"before"`
+	After         int    `json:"after"`
+	IncludeBinary bool   `json:"include_binary,omitempty"`
+}
+
+// grepLine is a streamed matching or context line (or the summary)
+type grepLine struct {
+	Type       string `json:"type"`
+	File       string `json:"file"`
+	Line       int    `json:"line"`
+	TextOffset int64  `json:"text_offset"`
+	Text       string `json:"text"`
+	Matches    int    `json:"matches"`
+}
+
+// Grep is printing the streamed lines the same way as grep -r
+// it exits with 1 when nothing matched like grep
+func (st *store) Grep() {
+	grepRequest := &grepRequest{}
+	var lineNumbers, byteOffsets, noFileNames bool
+	var contextLines int
+
+	flags := flag.NewFlagSet(st.command, flag.ExitOnError)
+	flags.BoolVar(&grepRequest.IgnoreCase, "i", false, "ignore case distinctions")
+	flags.IntVar(&grepRequest.Before, "B", 0, "print lines of leading context")
+	flags.IntVar(&grepRequest.After, "A", 0, "print lines of trailing context")
+	flags.IntVar(&contextLines, "C", 0, "print lines of leading and trailing context")
+	flags.BoolVar(&lineNumbers, "n", false, "print line numbers")
+	flags.BoolVar(&byteOffsets, "b", false, "print the byte offset of lines in the decoded text")
+	flags.BoolVar(&noFileNames, "h", false, "do not print file names")
+	flags.StringVar(&grepRequest.Prefix, "prefix", "", "only files with the name prefix (directory)")
+	flags.BoolVar(&grepRequest.IncludeBinary, "include-binary", false, "grep binary files as well")
+	args := parseFlags(flags, st.options)
+	if len(args) == 0 {
+		fmt.Println("no pattern is specified")
+		os.Exit(2)
+	}
+	grepRequest.Pattern = args[0]
+	for _, arg := range args[1:] {
+		if strings.ContainsAny(arg, "*?[") {
+			grepRequest.Globs = append(grepRequest.Globs, arg)
+		} else {
+			grepRequest.Files = append(grepRequest.Files, arg)
+		}
+	}
+	if contextLines > 0 {
+		if grepRequest.Before == 0 {
+			grepRequest.Before = contextLines
+		}
+		if grepRequest.After == 0 {
+			grepRequest.After = contextLines
+		}
+	}
+
+	body, err := st.createAndExecuteStreamRequest(http.MethodGet, "grep", grepRequest)
+	if err != nil {
+		fmt.Printf("error occured while grepping the files : %v", err)
+		os.Exit(2)
+	}
+	defer body.Close()
+
+	withContext := grepRequest.Before > 0 || grepRequest.After > 0
+	lastFile, lastLine := "", 0
+	matches := 0
+	decoder := json.NewDecoder(body)
+	for {
+		line := &grepLine{}
+		if err := decoder.Decode(line); err != nil {
+			if err == io.EOF {
+				break
+			}
+			fmt.Printf("error while reading the response from server %v", err)
+			os.Exit(2)
+		}
+		if line.Type == "summary" {
+			matches = line.Matches
+			continue
+		}
+
+		// same as grep, groups of lines which are not adjacent are separated
+		if withContext && lastFile != "" && (line.File != lastFile || line.Line != lastLine+1) {
+			fmt.Println("--")
+		}
+		lastFile, lastLine = line.File, line.Line
+
+		separator := "-"
+		if line.Type == "match" {
+			separator = ":"
+		}
+		prefix := ""
+		if !noFileNames {
+			prefix += line.File + separator
+		}
+		if lineNumbers {
+			prefix += fmt.Sprint(line.Line) + separator
+		}
+		if byteOffsets {
+			prefix += fmt.Sprint(line.TextOffset) + separator
+		}
+		fmt.Println(prefix + line.Text)
+	}
+
+	if matches == 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,73 @@
+package storemanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"text/tabwriter"
+)
+
+// fileInfoRequest is request when the details of a file are fetched
+type fileInfoRequest struct {
+	Name string `json:"name"`
+}
+
+// fileMeta is the sniffed content type of a stored file
+type fileMeta struct {
+	Name             string `json:"name"`
+	ContentType      string `json:"content_type"`
+	Text             bool   `json:"text"`
+	Size             int64  `json:"size"`
+	Extractor        string `json:"extractor"`
+	Encoding         string `json:"encoding"`
+	BOM              bool   `json:"bom"`
+	LineEndings      string `json:"line_endings"`
+	OriginalEncoding string `json:"original_encoding"`
+}
+
+// FileInfo is printing the content type, text or binary, size, encoding and
+// line endings of stored files
+// store info <name>...
+func (st *store) FileInfo() {
+	if len(st.options) == 0 {
+		fmt.Println("file name is required")
+		os.Exit(1)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FILE\tCONTENT TYPE\tKIND\tSIZE\tENCODING\tLINE ENDINGS")
+	for _, name := range st.options {
+		bodyBytes, err := st.createAndExecuteHTTPRequest(http.MethodGet, "fileinfo", &fileInfoRequest{Name: name})
+		if err != nil {
+			w.Flush()
+			fmt.Printf("error occured while getting the file info : %v", err)
+			os.Exit(1)
+		}
+
+		meta := &fileMeta{}
+		if err := json.Unmarshal(bodyBytes, meta); err != nil {
+			w.Flush()
+			fmt.Println("error while reading the response from server")
+			os.Exit(1)
+		}
+		kind, encoding, lineEndings := "binary", "-", "-"
+		if meta.Text {
+			kind, encoding, lineEndings = "text", meta.Encoding, meta.LineEndings
+		}
+		if meta.Extractor != "" {
+			kind = "document (" + meta.Extractor + ")"
+		}
+		if meta.BOM {
+			encoding += " (bom)"
+		}
+		if meta.OriginalEncoding != "" {
+			encoding += " (from " + meta.OriginalEncoding + ")"
+		}
+		if lineEndings == "" {
+			lineEndings = "-"
+		}
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\n", meta.Name, meta.ContentType, kind, meta.Size, encoding, lineEndings)
+	}
+	w.Flush()
+}
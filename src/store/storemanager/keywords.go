@@ -0,0 +1,127 @@
+package storemanager
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"text/tabwriter"
+)
+
+// keywordsRequest is request when tf-idf keywords are fetched
+type keywordsRequest struct {
+	analysisOptions
+	Limit int `json:"limit"`
+}
+
+// keyword is a word of a file with its tf-idf weight
+type keyword struct {
+	Word  string  `json:"word"`
+	Count int     `json:"count"`
+	TFIDF float64 `json:"tf_idf"`
+}
+
+// keywordsResponse is response when tf-idf keywords are fetched
+type keywordsResponse struct {
+	Files []struct {
+		Name     string    `json:"name"`
+		Keywords []keyword `json:"keywords"`
+	} `json:"files"`
+}
+
+// similarRequest is request when similar files are fetched
+type similarRequest struct {
+	analysisOptions
+	File  string `json:"file,omitempty"`
+	Text  string `json:"text,omitempty"`
+	Limit int    `json:"limit"`
+}
+
+// similarResponse is response when similar files are fetched
+type similarResponse struct {
+	Files []struct {
+		Name  string  `json:"name"`
+		Score float64 `json:"score"`
+	} `json:"files"`
+}
+
+func (st *store) Keywords() {
+	keywordsRequest := &keywordsRequest{}
+
+	flags := flag.NewFlagSet(st.command, flag.ExitOnError)
+	flags.IntVar(&keywordsRequest.Limit, "limit", 10, "number of keywords per file")
+	options := analysisFlags(flags)
+	options.filterArgs(parseFlags(flags, st.options))
+	keywordsRequest.analysisOptions = *options
+
+	bodyBytes, err := st.createAndExecuteHTTPRequest(http.MethodGet, "keywords", keywordsRequest)
+	if err != nil {
+		fmt.Printf("error occured while getting the keywords : %v", err)
+		os.Exit(1)
+	}
+
+	keywordsResponse := &keywordsResponse{}
+	if err := json.Unmarshal(bodyBytes, keywordsResponse); err != nil {
+		fmt.Println("error while reading the response from server")
+		os.Exit(1)
+	}
+
+	if len(keywordsResponse.Files) == 0 {
+		fmt.Println("no files exist on server")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FILE\tTF-IDF\tCOUNT\tKEYWORD")
+	for _, file := range keywordsResponse.Files {
+		for _, keyword := range file.Keywords {
+			fmt.Fprintf(w, "%v\t%.4f\t%v\t%v\n", file.Name, keyword.TFIDF, keyword.Count, keyword.Word)
+		}
+	}
+	w.Flush()
+}
+
+func (st *store) Similar() {
+	similarRequest := &similarRequest{}
+
+	flags := flag.NewFlagSet(st.command, flag.ExitOnError)
+	flags.IntVar(&similarRequest.Limit, "limit", 10, "number of similar files")
+	flags.StringVar(&similarRequest.Text, "text", "", "text snippet to compare with instead of a file")
+	options := analysisFlags(flags)
+	args := parseFlags(flags, st.options)
+	if similarRequest.Text == "" {
+		if len(args) == 0 {
+			fmt.Println("no file or --text is specified")
+			os.Exit(1)
+		}
+		similarRequest.File = args[0]
+		args = args[1:]
+	}
+	options.filterArgs(args)
+	similarRequest.analysisOptions = *options
+
+	bodyBytes, err := st.createAndExecuteHTTPRequest(http.MethodGet, "similar", similarRequest)
+	if err != nil {
+		fmt.Printf("error occured while getting the similar files : %v", err)
+		os.Exit(1)
+	}
+
+	similarResponse := &similarResponse{}
+	if err := json.Unmarshal(bodyBytes, similarResponse); err != nil {
+		fmt.Println("error while reading the response from server")
+		os.Exit(1)
+	}
+
+	if len(similarResponse.Files) == 0 {
+		fmt.Println("no similar files found on server")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SCORE\tFILE")
+	for _, file := range similarResponse.Files {
+		fmt.Fprintf(w, "%.4f\t%v\n", file.Score, file.Name)
+	}
+	w.Flush()
+}
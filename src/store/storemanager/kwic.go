@@ -0,0 +1,92 @@
+package storemanager
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"unicode/utf8"
+)
+
+// kwicRequest is request when the occurrences of a word are fetched with context
+type kwicRequest struct {
+	analysisOptions
+	Word    string `json:"word"`
+	Context int    `json:"context"`
+	Limit   int    `json:"limit"`
+}
+
+// kwicOccurrence is an occurrence of a word with the words around it
+type kwicOccurrence struct {
+	Line  int    `json:"line"`
+	Left  string `json:"left"`
+	Word  string `json:"word"`
+	Right string `json:"right"`
+}
+
+// kwicFile is the occurrences of a word in a file
+type kwicFile struct {
+	Name        string           `json:"name"`
+	Occurrences []kwicOccurrence `json:"occurrences"`
+}
+
+// kwicResponse is response when the occurrences of a word are fetched with context
+type kwicResponse struct {
+	Word  string     `json:"word"`
+	Total int        `json:"total"`
+	Files []kwicFile `json:"files"`
+}
+
+func (st *store) KWIC() {
+	kwicRequest := &kwicRequest{}
+
+	flags := flag.NewFlagSet(st.command, flag.ExitOnError)
+	flags.IntVar(&kwicRequest.Context, "n", 5, "number of words shown on each side")
+	flags.IntVar(&kwicRequest.Limit, "limit", 0, "maximum number of occurrences (all when 0)")
+	options := analysisFlags(flags)
+	args := parseFlags(flags, st.options)
+	if len(args) == 0 {
+		fmt.Println("no word is specified")
+		os.Exit(1)
+	}
+	if kwicRequest.Context < 1 {
+		fmt.Printf("invalid number of context words %v", kwicRequest.Context)
+		os.Exit(1)
+	}
+	kwicRequest.Word = args[0]
+	options.filterArgs(args[1:])
+	kwicRequest.analysisOptions = *options
+
+	bodyBytes, err := st.createAndExecuteHTTPRequest(http.MethodGet, "kwic", kwicRequest)
+	if err != nil {
+		fmt.Printf("error occured while getting the concordance : %v", err)
+		os.Exit(1)
+	}
+
+	kwicResponse := &kwicResponse{}
+	if err := json.Unmarshal(bodyBytes, kwicResponse); err != nil {
+		fmt.Println("error while reading the response from server")
+		os.Exit(1)
+	}
+
+	if kwicResponse.Total == 0 {
+		fmt.Printf("word %v not found on server\n", kwicResponse.Word)
+		return
+	}
+
+	// left contexts are right aligned so that the words line up in a column
+	for _, file := range kwicResponse.Files {
+		width := 0
+		for _, occurrence := range file.Occurrences {
+			if n := utf8.RuneCountInString(occurrence.Left); n > width {
+				width = n
+			}
+		}
+		fmt.Printf("==> %v <==\n", file.Name)
+		for _, occurrence := range file.Occurrences {
+			fmt.Printf("%6d: %*s [%v] %v\n", occurrence.Line, width, occurrence.Left, occurrence.Word, occurrence.Right)
+		}
+	}
+	fmt.Printf("%v occurrences of %v\n", kwicResponse.Total, kwicResponse.Word)
+}
@@ -0,0 +1,113 @@
+package storemanager
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// headRequest is request when the first or last lines of a file are read
+type headRequest struct {
+	Name  string `json:"name"`
+	Lines int    `json:"lines"`
+}
+
+// linesResponse is lines of a stored file
+type linesResponse struct {
+	Lines []string `json:"lines"`
+}
+
+// followRequest is request when a stored file is followed
+type followRequest struct {
+	Name  string `json:"name"`
+	Lines *int   `json:"lines"`
+}
+
+// followRecord is a streamed line of a followed file
+type followRecord struct {
+	Type   string `json:"type"`
+	Offset int64  `json:"offset"`
+	Text   string `json:"text"`
+}
+
+// Head is printing the first lines of a stored file
+// store head [-n 10] <name>
+func (st *store) Head() {
+	st.printLines("head", false)
+}
+
+// Tail is printing the last lines of a stored file and, with -f, every line
+// appended to it until it is interrupted or the file is removed
+// store tail [-f] [-n 10] <name>
+func (st *store) Tail() {
+	st.printLines("tail", true)
+}
+
+// printLines is reading the first or last lines of a file without
+// downloading it, the last lines may be followed
+func (st *store) printLines(url string, followed bool) {
+	var follow bool
+	lines := 10
+	flags := flag.NewFlagSet(st.command, flag.ExitOnError)
+	if followed {
+		flags.BoolVar(&follow, "f", false, "print appended lines as they are written")
+	}
+	flags.IntVar(&lines, "n", 10, "number of lines")
+	args := parseFlags(flags, st.options)
+	if len(args) != 1 {
+		fmt.Println("a single file name is required")
+		os.Exit(1)
+	}
+	if follow {
+		st.follow(args[0], lines)
+		return
+	}
+
+	bodyBytes, err := st.createAndExecuteHTTPRequest(http.MethodGet, url, &headRequest{Name: args[0], Lines: lines})
+	if err != nil {
+		fmt.Printf("error occured while reading the lines of the file : %v", err)
+		os.Exit(1)
+	}
+	linesResponse := &linesResponse{}
+	if err := json.Unmarshal(bodyBytes, linesResponse); err != nil {
+		fmt.Println("error while reading the response from server")
+		os.Exit(1)
+	}
+	for _, line := range linesResponse.Lines {
+		fmt.Println(line)
+	}
+}
+
+// follow is printing the last lines of a file and then the appended ones
+func (st *store) follow(name string, lines int) {
+	body, err := st.createAndExecuteStreamRequest(http.MethodGet, "follow", &followRequest{Name: name, Lines: &lines})
+	if err != nil {
+		fmt.Printf("error occured while following the file : %v", err)
+		os.Exit(1)
+	}
+	defer body.Close()
+
+	decoder := json.NewDecoder(body)
+	for {
+		record := &followRecord{}
+		if err := decoder.Decode(record); err != nil {
+			if err == io.EOF {
+				return
+			}
+			fmt.Printf("error while reading the response from server %v", err)
+			os.Exit(1)
+		}
+		switch record.Type {
+		case "truncated":
+			fmt.Fprintf(os.Stderr, "store: %v: file truncated\n", name)
+		case "removed":
+			fmt.Fprintf(os.Stderr, "store: %v: file removed\n", name)
+			return
+		default:
+			fmt.Println(record.Text)
+		}
+	}
+}
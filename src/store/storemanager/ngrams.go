@@ -0,0 +1,64 @@
+package storemanager
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// ngramRequest is request when frequent n-grams are counted
+type ngramRequest struct {
+	analysisOptions
+	N      int    `json:"n"`
+	Limit  int    `json:"limit"`
+	Order  string `json:"order"`
+	Offset int    `json:"offset"`
+	Cursor string `json:"cursor"`
+}
+
+// ngramResponse is response when frequent n-grams are counted
+type ngramResponse struct {
+	NGrams     []wordFrequency `json:"ngrams"`
+	Total      int             `json:"total"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+func (st *store) NGrams() {
+	ngramRequest := &ngramRequest{}
+
+	flags := flag.NewFlagSet(st.command, flag.ExitOnError)
+	flags.IntVar(&ngramRequest.N, "n", 2, "number of words in a n-gram")
+	flags.IntVar(&ngramRequest.Limit, "limit", 10, "number of n-grams to return")
+	flags.StringVar(&ngramRequest.Order, "order", "dsc", "order of the n-grams asc|dsc")
+	flags.IntVar(&ngramRequest.Offset, "offset", 0, "number of ranked n-grams to skip")
+	flags.StringVar(&ngramRequest.Cursor, "cursor", "", "cursor returned by a previous page")
+	options := analysisFlags(flags)
+	options.filterArgs(parseFlags(flags, st.options))
+	ngramRequest.analysisOptions = *options
+
+	if ngramRequest.Order != "asc" && ngramRequest.Order != "dsc" {
+		fmt.Printf("invalid order provided %v", ngramRequest.Order)
+		os.Exit(1)
+	}
+
+	bodyBytes, err := st.createAndExecuteHTTPRequest(http.MethodGet, "ngrams", ngramRequest)
+	if err != nil {
+		fmt.Printf("error occured while getting the n-grams : %v", err)
+		os.Exit(1)
+	}
+
+	ngramResponse := &ngramResponse{}
+	if err := json.Unmarshal(bodyBytes, ngramResponse); err != nil {
+		fmt.Println("error while reading the response from server")
+		os.Exit(1)
+	}
+
+	if len(ngramResponse.NGrams) == 0 {
+		fmt.Println("no n-grams found on server")
+		return
+	}
+
+	printRanked("NGRAM", ngramResponse.NGrams, ngramResponse.NextCursor)
+}
@@ -0,0 +1,147 @@
+package storemanager
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// piiRule is a regular expression finding pii, Luhn is checking card numbers
+type piiRule struct {
+	Name    string `json:"name"`
+	Pattern string `json:"pattern"`
+	Luhn    bool   `json:"luhn,omitempty"`
+}
+
+// piiConfig is the pii rule set and the action on uploaded files
+type piiConfig struct {
+	Action    string    `json:"action"`
+	Redaction string    `json:"redaction,omitempty"`
+	Rules     []piiRule `json:"rules,omitempty"`
+}
+
+// piiFinding is pii found in a file, Text is masked
+type piiFinding struct {
+	Rule  string `json:"rule"`
+	Line  int    `json:"line"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Text  string `json:"text"`
+}
+
+// piiFileReport is the pii found in a file
+type piiFileReport struct {
+	Name     string         `json:"name"`
+	Findings []piiFinding   `json:"findings"`
+	Counts   map[string]int `json:"counts"`
+}
+
+// piiReportRequest is request when files are scanned for pii
+type piiReportRequest struct {
+	fileFilter
+	IncludeBinary bool `json:"include_binary,omitempty"`
+}
+
+// piiReportResponse is response when files are scanned for pii
+type piiReportResponse struct {
+	Scanned int             `json:"scanned"`
+	Files   []piiFileReport `json:"files"`
+}
+
+// PII is managing the pii rule set and reporting pii of stored files
+// store pii report [file...] | config | set <config.json>
+func (st *store) PII() {
+	if len(st.options) == 0 {
+		fmt.Println("pii command (report, config, set) is not specified")
+		os.Exit(1)
+	}
+
+	switch st.options[0] {
+	case "report":
+		piiReportRequest := &piiReportRequest{}
+		flags := flag.NewFlagSet(st.command+" report", flag.ExitOnError)
+		flags.StringVar(&piiReportRequest.Prefix, "prefix", "", "only files with the name prefix (directory)")
+		flags.BoolVar(&piiReportRequest.IncludeBinary, "include-binary", false, "scan binary files as well")
+		piiReportRequest.filterArgs(parseFlags(flags, st.options[1:]))
+
+		bodyBytes, err := st.createAndExecuteHTTPRequest(http.MethodGet, "piireport", piiReportRequest)
+		if err != nil {
+			fmt.Printf("error occured while getting the pii report : %v", err)
+			os.Exit(1)
+		}
+
+		piiReportResponse := &piiReportResponse{}
+		if err := json.Unmarshal(bodyBytes, piiReportResponse); err != nil {
+			fmt.Println("error while reading the response from server")
+			os.Exit(1)
+		}
+		printPIIReports(piiReportResponse.Files)
+		fmt.Printf("%v of %v files have pii\n", len(piiReportResponse.Files), piiReportResponse.Scanned)
+	case "config":
+		bodyBytes, err := st.createAndExecuteHTTPRequest(http.MethodGet, "pii", nil)
+		if err != nil {
+			fmt.Printf("error occured while getting the pii config : %v", err)
+			os.Exit(1)
+		}
+
+		config := &piiConfig{}
+		if err := json.Unmarshal(bodyBytes, config); err != nil {
+			fmt.Println("error while reading the response from server")
+			os.Exit(1)
+		}
+		fmt.Println("action    :", config.Action)
+		fmt.Println("redaction :", config.Redaction)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "RULE\tLUHN\tPATTERN")
+		for _, rule := range config.Rules {
+			fmt.Fprintf(w, "%v\t%v\t%v\n", rule.Name, rule.Luhn, rule.Pattern)
+		}
+		w.Flush()
+	case "set":
+		if len(st.options) != 2 {
+			fmt.Println("pii config file is required")
+			os.Exit(1)
+		}
+
+		content, err := ioutil.ReadFile(st.options[1])
+		if err != nil {
+			fmt.Printf("error occured while reading the file %v", err)
+			os.Exit(1)
+		}
+		config := &piiConfig{}
+		if err := json.Unmarshal(content, config); err != nil {
+			fmt.Printf("pii config file is not valid json %v", err)
+			os.Exit(1)
+		}
+
+		if _, err := st.createAndExecuteHTTPRequest(http.MethodPut, "pii", config); err != nil {
+			fmt.Printf("error occured while updating the pii config :%v", err)
+			os.Exit(1)
+		}
+		fmt.Println("pii config updated successfully")
+	default:
+		fmt.Printf("pii command %v is not valid", st.options[0])
+		os.Exit(1)
+	}
+}
+
+// printPIIReports is printing the findings of every file
+func printPIIReports(reports []piiFileReport) {
+	for _, report := range reports {
+		rules := make([]string, 0, len(report.Counts))
+		for rule, count := range report.Counts {
+			rules = append(rules, fmt.Sprintf("%v %v", count, rule))
+		}
+		sort.Strings(rules)
+		fmt.Printf("==> %v <== %v\n", report.Name, strings.Join(rules, ", "))
+		for _, finding := range report.Findings {
+			fmt.Printf("%6d:%-4d %-8v %v\n", finding.Line, finding.Start+1, finding.Rule, finding.Text)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package storemanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// namespacePolicy is the upload policy of a namespace
+type namespacePolicy struct {
+	Secrets   string   `json:"secrets,omitempty"`
+	MIMETypes []string `json:"mime_types,omitempty"`
+}
+
+// policyConfig is the default upload policy and the policies of namespaces
+type policyConfig struct {
+	Default    namespacePolicy            `json:"default"`
+	Namespaces map[string]namespacePolicy `json:"namespaces"`
+}
+
+// Policy is managing the upload policies and listing quarantined files
+// store policy show | set <policies.json> | quarantine
+func (st *store) Policy() {
+	if len(st.options) == 0 {
+		fmt.Println("policy command (show, set, quarantine) is not specified")
+		os.Exit(1)
+	}
+
+	switch st.options[0] {
+	case "show":
+		bodyBytes, err := st.createAndExecuteHTTPRequest(http.MethodGet, "policies", nil)
+		if err != nil {
+			fmt.Printf("error occured while getting the policies : %v", err)
+			os.Exit(1)
+		}
+
+		config := &policyConfig{}
+		if err := json.Unmarshal(bodyBytes, config); err != nil {
+			fmt.Println("error while reading the response from server")
+			os.Exit(1)
+		}
+
+		namespaces := make([]string, 0, len(config.Namespaces))
+		for namespace := range config.Namespaces {
+			namespaces = append(namespaces, namespace)
+		}
+		sort.Strings(namespaces)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAMESPACE\tSECRETS\tMIME TYPES")
+		fmt.Fprintf(w, "(default)\t%v\t%v\n", config.Default.Secrets, mimeTypesValue(config.Default.MIMETypes, "any"))
+		for _, namespace := range namespaces {
+			policy := config.Namespaces[namespace]
+			fmt.Fprintf(w, "%v\t%v\t%v\n", namespace, policyValue(policy.Secrets), mimeTypesValue(policy.MIMETypes, "(default)"))
+		}
+		w.Flush()
+	case "set":
+		if len(st.options) != 2 {
+			fmt.Println("policies file is required")
+			os.Exit(1)
+		}
+
+		content, err := ioutil.ReadFile(st.options[1])
+		if err != nil {
+			fmt.Printf("error occured while reading the file %v", err)
+			os.Exit(1)
+		}
+		config := &policyConfig{}
+		if err := json.Unmarshal(content, config); err != nil {
+			fmt.Printf("policies file is not valid json %v", err)
+			os.Exit(1)
+		}
+
+		if _, err := st.createAndExecuteHTTPRequest(http.MethodPut, "policies", config); err != nil {
+			fmt.Printf("error occured while updating the policies :%v", err)
+			os.Exit(1)
+		}
+		fmt.Println("policies updated successfully")
+	case "quarantine":
+		bodyBytes, err := st.createAndExecuteHTTPRequest(http.MethodGet, "quarantine", nil)
+		if err != nil {
+			fmt.Printf("error occured while listing the quarantine : %v", err)
+			os.Exit(1)
+		}
+
+		names := []string{}
+		if err := json.Unmarshal(bodyBytes, &names); err != nil {
+			fmt.Println("error while reading the response from server")
+			os.Exit(1)
+		}
+		if len(names) == 0 {
+			fmt.Println("no files are quarantined")
+			return
+		}
+		for _, name := range names {
+			fmt.Println(" ", name)
+		}
+	default:
+		fmt.Printf("policy command %v is not valid", st.options[0])
+		os.Exit(1)
+	}
+}
+
+// policyValue is showing a policy field taken from the default policy
+func policyValue(value string) string {
+	if value == "" {
+		return "(default)"
+	}
+	return value
+}
+
+// mimeTypesValue is showing the allowed content types of a policy
+func mimeTypesValue(mimeTypes []string, none string) string {
+	if len(mimeTypes) == 0 {
+		return none
+	}
+	return strings.Join(mimeTypes, ",")
+}
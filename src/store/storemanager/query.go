@@ -0,0 +1,101 @@
+package storemanager
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// queryRequest is request when json files are queried
+type queryRequest struct {
+	fileFilter
+	Query string `json:"query"`
+	Limit int    `json:"limit,omitempty"`
+}
+
+// queryRecord is a streamed match, a document which is not json or the summary
+type queryRecord struct {
+	Type      string          `json:"type"`
+	File      string          `json:"file"`
+	Line      int             `json:"line"`
+	Path      string          `json:"path"`
+	Value     json.RawMessage `json:"value"`
+	Error     string          `json:"error"`
+	Matches   int             `json:"matches"`
+	Truncated bool            `json:"truncated"`
+}
+
+// Query is printing the values selected by a JSONPath query in the stored
+// json and ndjson files, it exits with 1 when nothing matched like grep
+// store query [--values] [-r] [--limit n] [--prefix dir/] <query> [files]
+func (st *store) Query() {
+	queryRequest := &queryRequest{}
+	var values, raw bool
+	flags := flag.NewFlagSet(st.command, flag.ExitOnError)
+	flags.BoolVar(&values, "values", false, "print only the values")
+	flags.BoolVar(&raw, "r", false, "print strings without quotes")
+	flags.IntVar(&queryRequest.Limit, "limit", 0, "stop after this many values")
+	flags.StringVar(&queryRequest.Prefix, "prefix", "", "only files with the name prefix (directory)")
+	args := parseFlags(flags, st.options)
+	if len(args) == 0 {
+		fmt.Println("no query is specified")
+		os.Exit(2)
+	}
+	queryRequest.Query = args[0]
+	queryRequest.filterArgs(args[1:])
+
+	body, err := st.createAndExecuteStreamRequest(http.MethodGet, "query", queryRequest)
+	if err != nil {
+		fmt.Printf("error occured while querying the files : %v", err)
+		os.Exit(2)
+	}
+	defer body.Close()
+
+	summary := &queryRecord{}
+	decoder := json.NewDecoder(body)
+	for {
+		record := &queryRecord{}
+		if err := decoder.Decode(record); err != nil {
+			if err == io.EOF {
+				break
+			}
+			fmt.Printf("error while reading the response from server %v", err)
+			os.Exit(2)
+		}
+
+		location := record.File
+		if record.Line > 0 {
+			location += fmt.Sprintf(":%v", record.Line)
+		}
+		switch record.Type {
+		case "summary":
+			summary = record
+		case "error":
+			fmt.Fprintf(os.Stderr, "%v: not json, %v\n", location, record.Error)
+		default:
+			value := string(record.Value)
+			if raw && strings.HasPrefix(value, `"`) {
+				var s string
+				if json.Unmarshal(record.Value, &s) == nil {
+					value = s
+				}
+			}
+			if values {
+				fmt.Println(value)
+			} else {
+				fmt.Printf("%v:%v: %v\n", location, record.Path, value)
+			}
+		}
+	}
+
+	if summary.Truncated {
+		fmt.Fprintf(os.Stderr, "stopped after %v values\n", summary.Matches)
+	}
+	if summary.Matches == 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,89 @@
+package storemanager
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// searchResponse is response when files are searched
+type searchResponse struct {
+	Query string `json:"query"`
+	Total int    `json:"total"`
+	Hits  []struct {
+		Name     string  `json:"name"`
+		Score    float64 `json:"score"`
+		Matches  int     `json:"matches"`
+		Snippets []struct {
+			Line       int    `json:"line"`
+			Text       string `json:"text"`
+			Highlights []struct {
+				Start int `json:"start"`
+				End   int `json:"end"`
+			} `json:"highlights"`
+		} `json:"snippets"`
+	} `json:"hits"`
+}
+
+func (st *store) Search() {
+	var limit, offset int
+	flags := flag.NewFlagSet(st.command, flag.ExitOnError)
+	flags.IntVar(&limit, "limit", 10, "number of files to return")
+	flags.IntVar(&offset, "offset", 0, "number of ranked files to skip")
+	args := parseFlags(flags, st.options)
+	if len(args) == 0 {
+		fmt.Println("no search query is specified")
+		os.Exit(1)
+	}
+
+	values := url.Values{}
+	values.Set("q", strings.Join(args, " "))
+	values.Set("limit", strconv.Itoa(limit))
+	values.Set("offset", strconv.Itoa(offset))
+	bodyBytes, err := st.createAndExecuteHTTPRequest(http.MethodGet, "search?"+values.Encode(), nil)
+	if err != nil {
+		fmt.Printf("error occured while searching the files : %v", err)
+		os.Exit(1)
+	}
+
+	searchResponse := &searchResponse{}
+	if err := json.Unmarshal(bodyBytes, searchResponse); err != nil {
+		fmt.Println("error while reading the response from server")
+		os.Exit(1)
+	}
+
+	if len(searchResponse.Hits) == 0 {
+		fmt.Println("no files are matching")
+		return
+	}
+
+	start, end := highlightMarkers()
+	for _, hit := range searchResponse.Hits {
+		fmt.Printf("%v (score %.3f, %v matches)\n", hit.Name, hit.Score, hit.Matches)
+		for _, snippet := range hit.Snippets {
+			text := snippet.Text
+			// highlights are inserted from the end so earlier offsets stay valid
+			for i := len(snippet.Highlights) - 1; i >= 0; i-- {
+				h := snippet.Highlights[i]
+				text = text[:h.Start] + start + text[h.Start:h.End] + end + text[h.End:]
+			}
+			fmt.Printf("  %v: %v\n", snippet.Line, text)
+		}
+	}
+	fmt.Printf("%v of %v files\n", len(searchResponse.Hits), searchResponse.Total)
+}
+
+// highlightMarkers is returning the markers around matches
+// terminals get bold text, anything else brackets
+func highlightMarkers() (string, string) {
+	info, err := os.Stdout.Stat()
+	if err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		return "\033[1m", "\033[0m"
+	}
+	return "[", "]"
+}
@@ -0,0 +1,85 @@
+package storemanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// stopWordList is an uploaded stop word list
+type stopWordList struct {
+	Name  string   `json:"name"`
+	Words []string `json:"words"`
+}
+
+// stopWordListsResponse is response when stop word lists are listed
+type stopWordListsResponse struct {
+	Builtin  []string `json:"builtin"`
+	Uploaded []string `json:"uploaded"`
+}
+
+// StopWords is managing stop word lists
+// store stopwords ls | add <name> <file> | rm <name>
+func (st *store) StopWords() {
+	if len(st.options) == 0 {
+		fmt.Println("stopwords command (ls, add, rm) is not specified")
+		os.Exit(1)
+	}
+
+	switch st.options[0] {
+	case "ls":
+		bodyBytes, err := st.createAndExecuteHTTPRequest(http.MethodGet, "stopwords", nil)
+		if err != nil {
+			fmt.Printf("error occured while fetching the stop word lists : %v", err)
+			os.Exit(1)
+		}
+
+		lists := &stopWordListsResponse{}
+		if err := json.Unmarshal(bodyBytes, lists); err != nil {
+			fmt.Println("error while reading the response from server")
+			os.Exit(1)
+		}
+		fmt.Println("built in :", strings.Join(lists.Builtin, " "))
+		fmt.Println("uploaded :", strings.Join(lists.Uploaded, " "))
+	case "add":
+		if len(st.options) != 3 {
+			fmt.Println("stop word list name and file are required")
+			os.Exit(1)
+		}
+
+		_, content, err := st.getFileContent(st.options[2])
+		if err != nil {
+			fmt.Printf("error occured while reading the file %v", err)
+			os.Exit(1)
+		}
+
+		_, err = st.createAndExecuteHTTPRequest(http.MethodPut, "stopwords", &stopWordList{
+			Name:  st.options[1],
+			Words: strings.Fields(string(content)),
+		})
+		if err != nil {
+			fmt.Printf("error occured while uploading the stop word list :%v", err)
+			os.Exit(1)
+		}
+		fmt.Println("stop word list uploaded successfully")
+	case "rm":
+		if len(st.options) != 2 {
+			fmt.Println("stop word list name is required")
+			os.Exit(1)
+		}
+
+		_, err := st.createAndExecuteHTTPRequest(http.MethodDelete, "stopwords", &stopWordList{
+			Name: st.options[1],
+		})
+		if err != nil {
+			fmt.Printf("error occured while deleting the stop word list :%v", err)
+			os.Exit(1)
+		}
+		fmt.Println("stop word list deleted successfully")
+	default:
+		fmt.Printf("stopwords command %v is not valid", st.options[0])
+		os.Exit(1)
+	}
+}
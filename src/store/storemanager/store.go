@@ -3,12 +3,14 @@ package storemanager
 import (
 	"bytes"
 	"encoding/json"
+	"flag"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"os"
-	"strconv"
 	"strings"
+	"text/tabwriter"
 )
 
 // StoreManager is an interface which is exposing functionalities
@@ -20,28 +22,125 @@ type StoreManager interface {
 	RemoveFile()
 	WordCounts()
 	WordFrequency()
+	StopWords()
+	NGrams()
+	WordFiles()
+	Keywords()
+	Similar()
+	Search()
+	Grep()
+	Vocabulary()
+	KWIC()
+	Collocations()
+	Compare()
+	Analyze()
+	Entities()
+	DownloadFile()
+	FileInfo()
+	Describe()
+	Query()
+	Append()
+	Head()
+	Tail()
+	Diff()
+	PII()
+	Policy()
 }
 
-// wordCountResponse is response when counting words
-type wordCountResponse struct {
-	Count int `json:"count"`
+// tokenizerOptions is selecting the tokenizer used by the server
+type tokenizerOptions struct {
+	Name          string `json:"name,omitempty"`
+	Pattern       string `json:"pattern,omitempty"`
+	Normalization string `json:"normalization,omitempty"`
+	KeepCase      bool   `json:"keep_case,omitempty"`
+	Entities      bool   `json:"entities,omitempty"`
+}
+
+// fileFilter is selecting the files of a word statistics request
+type fileFilter struct {
+	Files  []string `json:"files,omitempty"`
+	Globs  []string `json:"globs,omitempty"`
+	Prefix string   `json:"prefix,omitempty"`
+}
+
+// analysisOptions are options shared by all the word statistics requests
+type analysisOptions struct {
+	fileFilter
+	Tokenizer     tokenizerOptions `json:"tokenizer"`
+	StopWords     stringList       `json:"stop_words,omitempty"`
+	Stem          bool             `json:"stem,omitempty"`
+	IncludeBinary bool             `json:"include_binary,omitempty"`
+}
+
+// wordStatsRequest is request when word statistics are fetched
+type wordStatsRequest struct {
+	analysisOptions
+}
+
+// wordStats is wc style statistics
+type wordStats struct {
+	Words             int     `json:"words"`
+	UniqueWords       int     `json:"unique_words"`
+	Lines             int     `json:"lines"`
+	Characters        int     `json:"characters"`
+	Bytes             int     `json:"bytes"`
+	AverageWordLength float64 `json:"average_word_length"`
+}
+
+// fileWordStats is statistics of a single file
+type fileWordStats struct {
+	Name string `json:"name"`
+	wordStats
+}
+
+// wordStatsResponse is response when word statistics are fetched
+type wordStatsResponse struct {
+	Files []fileWordStats `json:"files"`
+	Total wordStats       `json:"total"`
+}
+
+// directoryStats is statistics of a directory and everything below it
+type directoryStats struct {
+	Name string `json:"name"`
+	wordStats
+	Files       []fileWordStats   `json:"files"`
+	Directories []*directoryStats `json:"directories"`
+}
+
+// wordFrequency is a word with its count and rank
+type wordFrequency struct {
+	Word  string `json:"word"`
+	Count int    `json:"count"`
+	Rank  int    `json:"rank"`
 }
 
 // wordFrequencyResponse is response when frequent words counted
 type wordFrequencyResponse struct {
-	Words []string `json:"words"`
+	Words      []wordFrequency `json:"words"`
+	Total      int             `json:"total"`
+	NextCursor string          `json:"next_cursor,omitempty"`
 }
 
 // wordFrequencyRequest is request when frequent words counted
 type wordFrequencyRequest struct {
-	Limit int    `json:"limit"`
-	Order string `json:"order"`
+	analysisOptions
+	Limit  int    `json:"limit"`
+	Order  string `json:"order"`
+	Offset int    `json:"offset"`
+	Cursor string `json:"cursor"`
 }
 
 // file is representing file details name and content
 type file struct {
-	Name    string `json:"name"`
-	Content []byte `json:"content"`
+	Name        string `json:"name"`
+	Content     []byte `json:"content"`
+	ToUTF8      bool   `json:"to_utf8,omitempty"`
+	LineEndings string `json:"line_endings,omitempty"`
+}
+
+// errorResponse is the body of an error response with a message
+type errorResponse struct {
+	Error string `json:"error"`
 }
 
 // store is implementing all the function that executes on different commands
@@ -94,59 +193,29 @@ func (st *store) ListFiles() {
 }
 
 func (st *store) AddFiles() {
-	if len(st.options) == 0 {
-		fmt.Println("no files are specified")
-	}
+	files := st.uploadFiles()
 
-	files := []file{}
-
-	for _, v := range st.options {
-		name, content, err := st.getFileContent(v)
-		if err != nil {
-			fmt.Printf("error occured while reading the files %v", err)
-			os.Exit(1)
-		}
-		files = append(files, file{
-			Name:    name,
-			Content: content,
-		})
-	}
-
-	_, err := st.createAndExecuteHTTPRequest(http.MethodPost, "addfiles", files)
+	bodyBytes, err := st.createAndExecuteHTTPRequest(http.MethodPost, "addfiles", files)
 	if err != nil {
 		fmt.Printf("error occured while adding the files :%v", err)
 		os.Exit(1)
 	}
 
 	fmt.Println("files added successfully")
+	printUploadResponse(bodyBytes)
 }
 
 func (st *store) UpdateFiles() {
-	if len(st.options) == 0 {
-		fmt.Println("no files are specified")
-	}
-
-	files := []file{}
-
-	for _, v := range st.options {
-		name, content, err := st.getFileContent(v)
-		if err != nil {
-			fmt.Printf("error occured while reading the files %v", err)
-			os.Exit(1)
-		}
-		files = append(files, file{
-			Name:    name,
-			Content: content,
-		})
-	}
+	files := st.uploadFiles()
 
-	_, err := st.createAndExecuteHTTPRequest(http.MethodPut, "updatefiles", files)
+	bodyBytes, err := st.createAndExecuteHTTPRequest(http.MethodPut, "updatefiles", files)
 	if err != nil {
 		fmt.Printf("error occured while updating the files :%v", err)
 		os.Exit(1)
 	}
 
 	fmt.Println("files updated successfully")
+	printUploadResponse(bodyBytes)
 }
 
 func (st *store) RemoveFile() {
@@ -158,9 +227,10 @@ func (st *store) RemoveFile() {
 		fmt.Println("more than one files are specified")
 	}
 
-	fileStructure := strings.Split(st.options[0], "/")
+	// the name is relative to the store so that files of a namespace or a
+	// directory can be removed
 	_, err := st.createAndExecuteHTTPRequest(http.MethodDelete, "removefile", &file{
-		Name: fileStructure[len(fileStructure)-1],
+		Name: st.options[0],
 	})
 	if err != nil {
 		fmt.Printf("error occured while deleting the file :%v", err)
@@ -171,66 +241,208 @@ func (st *store) RemoveFile() {
 }
 
 func (st *store) WordCounts() {
-	bodyBytes, err := st.createAndExecuteHTTPRequest(http.MethodGet, "wordscount", nil)
-	if err != nil {
-		fmt.Printf("error occured while getting the words count : %v", err)
+	var showLines, showWords, showBytes, showChars, showUnique bool
+	flags := flag.NewFlagSet(st.command, flag.ExitOnError)
+	flags.BoolVar(&showLines, "l", false, "print the line counts")
+	flags.BoolVar(&showWords, "w", false, "print the word counts")
+	flags.BoolVar(&showBytes, "c", false, "print the byte counts")
+	flags.BoolVar(&showChars, "m", false, "print the character counts")
+	flags.BoolVar(&showUnique, "u", false, "print the unique word counts")
+	tree := flags.Bool("tree", false, "print the counts rolled up per directory")
+	options := analysisFlags(flags)
+	options.filterArgs(parseFlags(flags, st.options))
+
+	// same as wc, without flags lines, words and bytes are printed
+	if !showLines && !showWords && !showBytes && !showChars && !showUnique {
+		showLines, showWords, showBytes = true, true, true
 	}
 
-	if len(bodyBytes) == 0 {
-		fmt.Println("no files exist on server")
-	}
-
-	wordCountResponse := &wordCountResponse{}
-
-	if err := json.Unmarshal(bodyBytes, &wordCountResponse); err != nil {
-		fmt.Println("error while reading the response from server")
+	printStats := func(stats wordStats, name string) {
+		columns := []string{}
+		if showLines {
+			columns = append(columns, fmt.Sprintf("%7d", stats.Lines))
+		}
+		if showWords {
+			columns = append(columns, fmt.Sprintf("%7d", stats.Words))
+		}
+		if showUnique {
+			columns = append(columns, fmt.Sprintf("%7d", stats.UniqueWords))
+		}
+		if showChars {
+			columns = append(columns, fmt.Sprintf("%7d", stats.Characters))
+		}
+		if showBytes {
+			columns = append(columns, fmt.Sprintf("%7d", stats.Bytes))
+		}
+		fmt.Println(strings.Join(columns, " "), name)
 	}
 
-	fmt.Printf("Total words are %v", wordCountResponse.Count)
-}
-
-func (st *store) WordFrequency() {
-	wordFrequency := &wordFrequencyRequest{
-		Limit: 10,
-		Order: "asc",
-	}
+	if *tree {
+		bodyBytes, err := st.createAndExecuteHTTPRequest(http.MethodGet, "wordstatstree", &wordStatsRequest{
+			analysisOptions: *options,
+		})
+		if err != nil {
+			fmt.Printf("error occured while getting the words count : %v", err)
+			os.Exit(1)
+		}
 
-	for i, v := range st.options {
-		if strings.HasPrefix(v, "--limit") || strings.HasPrefix(v, "-n") && i < len(st.options)-1 {
-			limit, err := strconv.Atoi(st.options[i+1])
-			if err != nil {
-				fmt.Printf("invalid limit provided %v", st.options[i+1])
-				os.Exit(1)
-			}
-			wordFrequency.Limit = limit
+		root := &directoryStats{}
+		if err := json.Unmarshal(bodyBytes, root); err != nil {
+			fmt.Println("error while reading the response from server")
+			os.Exit(1)
 		}
 
-		if strings.HasPrefix(v, "--order=") && i < len(st.options) {
-			order := strings.TrimPrefix(st.options[i], "--order=")
-			if order != "asc" && order != "dsc" {
-				fmt.Printf("invalid order provided %v", st.options[i])
-				os.Exit(1)
+		// same as du, a directory is printed after everything below it
+		var printTree func(directory *directoryStats)
+		printTree = func(directory *directoryStats) {
+			for _, file := range directory.Files {
+				printStats(file.wordStats, file.Name)
+			}
+			for _, subdir := range directory.Directories {
+				printTree(subdir)
 			}
-			wordFrequency.Order = order
+			name := directory.Name
+			if name == "" {
+				name = "./"
+			}
+			printStats(directory.wordStats, name)
 		}
+		printTree(root)
+		return
 	}
 
-	bodyBytes, err := st.createAndExecuteHTTPRequest(http.MethodGet, "wordsfrequency", wordFrequency)
+	bodyBytes, err := st.createAndExecuteHTTPRequest(http.MethodGet, "wordstats", &wordStatsRequest{
+		analysisOptions: *options,
+	})
 	if err != nil {
 		fmt.Printf("error occured while getting the words count : %v", err)
+		os.Exit(1)
 	}
 
-	if len(bodyBytes) == 0 {
+	wordStatsResponse := &wordStatsResponse{}
+
+	if err := json.Unmarshal(bodyBytes, &wordStatsResponse); err != nil {
+		fmt.Println("error while reading the response from server")
+		os.Exit(1)
+	}
+
+	if len(wordStatsResponse.Files) == 0 {
 		fmt.Println("no files exist on server")
+		return
+	}
+
+	for _, file := range wordStatsResponse.Files {
+		printStats(file.wordStats, file.Name)
+	}
+	if len(wordStatsResponse.Files) > 1 {
+		printStats(wordStatsResponse.Total, "total")
+	}
+}
+
+func (st *store) WordFrequency() {
+	wordFrequency := &wordFrequencyRequest{}
+
+	flags := flag.NewFlagSet(st.command, flag.ExitOnError)
+	flags.IntVar(&wordFrequency.Limit, "limit", 10, "number of words to return")
+	flags.IntVar(&wordFrequency.Limit, "n", 10, "number of words to return")
+	flags.StringVar(&wordFrequency.Order, "order", "asc", "order of the words asc|dsc")
+	flags.IntVar(&wordFrequency.Offset, "offset", 0, "number of ranked words to skip")
+	flags.StringVar(&wordFrequency.Cursor, "cursor", "", "cursor returned by a previous page")
+	options := analysisFlags(flags)
+	options.filterArgs(parseFlags(flags, st.options))
+	wordFrequency.analysisOptions = *options
+
+	if wordFrequency.Order != "asc" && wordFrequency.Order != "dsc" {
+		fmt.Printf("invalid order provided %v", wordFrequency.Order)
+		os.Exit(1)
+	}
+
+	bodyBytes, err := st.createAndExecuteHTTPRequest(http.MethodGet, "wordsfrequency", wordFrequency)
+	if err != nil {
+		fmt.Printf("error occured while getting the words count : %v", err)
+		os.Exit(1)
 	}
 
 	wordFrequencyResponse := &wordFrequencyResponse{}
 
 	if err := json.Unmarshal(bodyBytes, &wordFrequencyResponse); err != nil {
 		fmt.Println("error while reading the response from server")
+		os.Exit(1)
+	}
+
+	if len(wordFrequencyResponse.Words) == 0 {
+		fmt.Println("no words found on server")
+		return
+	}
+
+	printRanked("WORD", wordFrequencyResponse.Words, wordFrequencyResponse.NextCursor)
+}
+
+// printRanked is printing ranked words as a table with rank and count columns
+func printRanked(title string, words []wordFrequency, nextCursor string) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "RANK\tCOUNT\t%v\n", title)
+	for _, word := range words {
+		fmt.Fprintf(w, "%v\t%v\t%v\n", word.Rank, word.Count, word.Word)
+	}
+	w.Flush()
+
+	if nextCursor != "" {
+		fmt.Printf("more results available, next page --cursor %v\n", nextCursor)
+	}
+}
+
+// analysisFlags is registering the flags of the word statistics options
+// the options are filled once the flags are parsed
+func analysisFlags(flags *flag.FlagSet) *analysisOptions {
+	options := &analysisOptions{}
+	flags.StringVar(&options.Tokenizer.Name, "tokenizer", "", "tokenizer unicode|whitespace|regex")
+	flags.StringVar(&options.Tokenizer.Pattern, "pattern", "", "pattern of the regex tokenizer")
+	flags.StringVar(&options.Tokenizer.Normalization, "normalization", "", "unicode normalization nfc|nfkc|none")
+	flags.BoolVar(&options.Tokenizer.KeepCase, "keep-case", false, "do not fold the case of words")
+	flags.BoolVar(&options.Tokenizer.Entities, "entities", false, "count urls, emails, @mentions and #hashtags as single words")
+	flags.Var(&options.StopWords, "stop-words", "comma separated stop word lists (en,fr or uploaded list)")
+	flags.BoolVar(&options.Stem, "stem", false, "reduce english words to their stem")
+	flags.StringVar(&options.Prefix, "prefix", "", "only files with the name prefix (directory)")
+	flags.BoolVar(&options.IncludeBinary, "include-binary", false, "analyse binary files as well")
+	return options
+}
+
+// filterArgs is selecting the files given as arguments
+// arguments with *, ? or [ are glob patterns, others are file names
+func (ff *fileFilter) filterArgs(args []string) {
+	for _, arg := range args {
+		if strings.ContainsAny(arg, "*?[") {
+			ff.Globs = append(ff.Globs, arg)
+			continue
+		}
+		ff.Files = append(ff.Files, arg)
+	}
+}
+
+// parseFlags is parsing flags which may be mixed with positional arguments
+// positional arguments are returned in the order they were given,
+// everything after "--" is positional (store search -- -word)
+func parseFlags(flags *flag.FlagSet, args []string) []string {
+	rest := []string{}
+	for i, arg := range args {
+		if arg == "--" {
+			args, rest = args[:i], args[i+1:]
+			break
+		}
 	}
 
-	fmt.Printf("words are %v", wordFrequencyResponse.Words)
+	positional := []string{}
+	for {
+		// ExitOnError makes Parse exit on invalid flags
+		_ = flags.Parse(args)
+		args = flags.Args()
+		if len(args) == 0 {
+			return append(positional, rest...)
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
 }
 
 func (st *store) getFileContent(fPath string) (string, []byte, error) {
@@ -247,6 +459,18 @@ func (st *store) getFileContent(fPath string) (string, []byte, error) {
 }
 
 func (st *store) createAndExecuteHTTPRequest(method, url string, reqBody interface{}) ([]byte, error) {
+	body, err := st.createAndExecuteStreamRequest(method, url, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return ioutil.ReadAll(body)
+}
+
+// createAndExecuteStreamRequest is returning the response body to be read
+// while the server is still writing it, the caller has to close the body
+func (st *store) createAndExecuteStreamRequest(method, url string, reqBody interface{}) (io.ReadCloser, error) {
 	reqUrl := st.baseURL + url
 	requestBody := []byte{}
 	var err error
@@ -267,16 +491,15 @@ func (st *store) createAndExecuteHTTPRequest(method, url string, reqBody interfa
 	if err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
-
-	bodyBytes, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
 
 	if res.StatusCode >= 200 && res.StatusCode <= 299 {
-		return bodyBytes, nil
+		return res.Body, nil
 	}
 
+	defer res.Body.Close()
+	errorResponse := &errorResponse{}
+	if err := json.NewDecoder(res.Body).Decode(errorResponse); err == nil && errorResponse.Error != "" {
+		return nil, fmt.Errorf("called failed with statuscode: %v, %v", res.StatusCode, errorResponse.Error)
+	}
 	return nil, fmt.Errorf("called failed with statuscode: %v", res.StatusCode)
 }
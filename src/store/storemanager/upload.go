@@ -0,0 +1,89 @@
+package storemanager
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+)
+
+// secretFinding is a secret found in an uploaded file, Text is masked
+type secretFinding struct {
+	Kind  string `json:"kind"`
+	Line  int    `json:"line"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Text  string `json:"text"`
+}
+
+// secretFileReport is the secrets of an uploaded file with the policy action
+type secretFileReport struct {
+	Name      string          `json:"name"`
+	Namespace string          `json:"namespace"`
+	Action    string          `json:"action"`
+	Findings  []secretFinding `json:"findings"`
+}
+
+// uploadResponse is response when added or updated files were flagged
+type uploadResponse struct {
+	PII         []piiFileReport    `json:"pii"`
+	Secrets     []secretFileReport `json:"secrets"`
+	Quarantined []string           `json:"quarantined"`
+}
+
+// uploadFiles is reading the local files of an add or update command
+// --namespace is storing them below a namespace (directory) on the server,
+// --to-utf8 and --line-endings are converting them on the server
+func (st *store) uploadFiles() []file {
+	var namespace, lineEndings string
+	var toUTF8 bool
+	flags := flag.NewFlagSet(st.command, flag.ExitOnError)
+	flags.StringVar(&namespace, "namespace", "", "namespace (directory) the files are stored in")
+	flags.BoolVar(&toUTF8, "to-utf8", false, "convert utf-16 and latin-1 files to utf-8")
+	flags.StringVar(&lineEndings, "line-endings", "", "normalize line endings to lf|crlf")
+	args := parseFlags(flags, st.options)
+	if len(args) == 0 {
+		fmt.Println("no files are specified")
+	}
+
+	files := []file{}
+	for _, v := range args {
+		name, content, err := st.getFileContent(v)
+		if err != nil {
+			fmt.Printf("error occured while reading the files %v", err)
+			os.Exit(1)
+		}
+		files = append(files, file{
+			Name:        path.Join(namespace, name),
+			Content:     content,
+			ToUTF8:      toUTF8,
+			LineEndings: lineEndings,
+		})
+	}
+	return files
+}
+
+// printUploadResponse is printing the pii and secrets flagged by the server
+func printUploadResponse(bodyBytes []byte) {
+	uploadResponse := &uploadResponse{}
+	if err := json.Unmarshal(bodyBytes, uploadResponse); err != nil {
+		return
+	}
+	if len(uploadResponse.PII) > 0 {
+		fmt.Println("warning: pii found in the files")
+		printPIIReports(uploadResponse.PII)
+	}
+	if len(uploadResponse.Secrets) > 0 {
+		fmt.Println("warning: secrets found in the files")
+		for _, report := range uploadResponse.Secrets {
+			fmt.Printf("==> %v <== %v\n", report.Name, report.Action)
+			for _, finding := range report.Findings {
+				fmt.Printf("%6d:%-4d %-16v %v\n", finding.Line, finding.Start+1, finding.Kind, finding.Text)
+			}
+		}
+	}
+	for _, name := range uploadResponse.Quarantined {
+		fmt.Printf("%v was quarantined\n", name)
+	}
+}
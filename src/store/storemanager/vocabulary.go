@@ -0,0 +1,69 @@
+package storemanager
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"text/tabwriter"
+)
+
+// vocabularyWord is a word of the vocabulary with its count and edit distance
+type vocabularyWord struct {
+	Word     string `json:"word"`
+	Count    int    `json:"count"`
+	Distance int    `json:"distance"`
+}
+
+// vocabularyResponse is response when completions and fuzzy matches are fetched
+type vocabularyResponse struct {
+	Query       string           `json:"query"`
+	Completions []vocabularyWord `json:"completions"`
+	Fuzzy       []vocabularyWord `json:"fuzzy"`
+}
+
+func (st *store) Vocabulary() {
+	var limit, distance int
+	var damerau bool
+	flags := flag.NewFlagSet(st.command, flag.ExitOnError)
+	flags.IntVar(&limit, "limit", 10, "number of completions and fuzzy matches")
+	flags.IntVar(&distance, "distance", 1, "maximum edit distance of fuzzy matches")
+	flags.BoolVar(&damerau, "damerau", false, "count swapped adjacent letters as one edit")
+	args := parseFlags(flags, st.options)
+	if len(args) != 1 {
+		fmt.Println("one word is required")
+		os.Exit(1)
+	}
+
+	values := url.Values{}
+	values.Set("q", args[0])
+	values.Set("limit", strconv.Itoa(limit))
+	values.Set("distance", strconv.Itoa(distance))
+	if damerau {
+		values.Set("algorithm", "damerau")
+	}
+	bodyBytes, err := st.createAndExecuteHTTPRequest(http.MethodGet, "vocabulary?"+values.Encode(), nil)
+	if err != nil {
+		fmt.Printf("error occured while getting the vocabulary : %v", err)
+		os.Exit(1)
+	}
+
+	vocabularyResponse := &vocabularyResponse{}
+	if err := json.Unmarshal(bodyBytes, vocabularyResponse); err != nil {
+		fmt.Println("error while reading the response from server")
+		os.Exit(1)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MATCH\tDISTANCE\tCOUNT\tWORD")
+	for _, word := range vocabularyResponse.Completions {
+		fmt.Fprintf(w, "prefix\t-\t%v\t%v\n", word.Count, word.Word)
+	}
+	for _, word := range vocabularyResponse.Fuzzy {
+		fmt.Fprintf(w, "fuzzy\t%v\t%v\t%v\n", word.Distance, word.Count, word.Word)
+	}
+	w.Flush()
+}
@@ -0,0 +1,72 @@
+package storemanager
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"text/tabwriter"
+)
+
+// wordFilesRequest is request when files contributing to a word are fetched
+type wordFilesRequest struct {
+	analysisOptions
+	Word  string `json:"word"`
+	Limit int    `json:"limit"`
+}
+
+// wordFile is the count of a word in a file
+type wordFile struct {
+	Name  string  `json:"name"`
+	Count int     `json:"count"`
+	Share float64 `json:"share"`
+}
+
+// wordFilesResponse is response when files contributing to a word are fetched
+type wordFilesResponse struct {
+	Word  string     `json:"word"`
+	Count int        `json:"count"`
+	Files []wordFile `json:"files"`
+}
+
+func (st *store) WordFiles() {
+	wordFilesRequest := &wordFilesRequest{}
+
+	flags := flag.NewFlagSet(st.command, flag.ExitOnError)
+	flags.IntVar(&wordFilesRequest.Limit, "limit", 10, "number of files to return")
+	options := analysisFlags(flags)
+	args := parseFlags(flags, st.options)
+	if len(args) == 0 {
+		fmt.Println("no word is specified")
+		os.Exit(1)
+	}
+	wordFilesRequest.Word = args[0]
+	options.filterArgs(args[1:])
+	wordFilesRequest.analysisOptions = *options
+
+	bodyBytes, err := st.createAndExecuteHTTPRequest(http.MethodGet, "wordfiles", wordFilesRequest)
+	if err != nil {
+		fmt.Printf("error occured while getting the word files : %v", err)
+		os.Exit(1)
+	}
+
+	wordFilesResponse := &wordFilesResponse{}
+	if err := json.Unmarshal(bodyBytes, wordFilesResponse); err != nil {
+		fmt.Println("error while reading the response from server")
+		os.Exit(1)
+	}
+
+	if len(wordFilesResponse.Files) == 0 {
+		fmt.Printf("word %v is not found on server\n", wordFilesResponse.Word)
+		return
+	}
+
+	fmt.Printf("%v occurs %v times\n", wordFilesResponse.Word, wordFilesResponse.Count)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "COUNT\tSHARE\tFILE")
+	for _, file := range wordFilesResponse.Files {
+		fmt.Fprintf(w, "%v\t%.1f%%\t%v\n", file.Count, file.Share*100, file.Name)
+	}
+	w.Flush()
+}